@@ -0,0 +1,272 @@
+package mini
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+/*
+UTF8Policy chooses what happens to lines that aren't valid UTF-8.
+*/
+type UTF8Policy int
+
+const (
+	//UTF8Unchecked passes invalid bytes through as they are
+	UTF8Unchecked UTF8Policy = iota
+	//UTF8Reject stops reading with an error giving the line and byte
+	UTF8Reject
+	//UTF8Replace replaces each invalid sequence with U+FFFD, the Unicode replacement character
+	UTF8Replace
+)
+
+var (
+	bomUTF8    = []byte{0xef, 0xbb, 0xbf}
+	bomUTF16LE = []byte{0xff, 0xfe}
+	bomUTF16BE = []byte{0xfe, 0xff}
+)
+
+//How a file was encoded, kept so that a Document is written back the way it was read
+type textEncoding int
+
+const (
+	encodingUTF8    textEncoding = iota //UTF-8 without a byte order mark
+	encodingUTF8BOM                     //UTF-8 starting with a byte order mark
+	encodingUTF16LE
+	encodingUTF16BE
+)
+
+/*
+Return a reader of UTF-8 text from input, along with how input is encoded. A byte order mark is removed,
+and text marked as UTF-16 by its byte order mark is converted to UTF-8. Other text is returned as it is.
+Only UTF-16 is buffered here, UTF-8 is left to the bufio.Scanner reading it. Text holds the bytes read to
+find the byte order mark, it is kept by the caller so that it isn't allocated separately.
+*/
+func decodeText(input io.Reader, text *startReader) (io.Reader, textEncoding) {
+	text.input = input
+	n, _ := io.ReadFull(input, text.buf[:])
+	start := text.buf[:n]
+
+	switch {
+	case bytes.HasPrefix(start, bomUTF8):
+		return input, encodingUTF8BOM
+	case bytes.HasPrefix(start, bomUTF16LE):
+		text.start = start[len(bomUTF16LE):]
+		return &utf16Reader{input: bufio.NewReader(text), order: binary.LittleEndian}, encodingUTF16LE
+	case bytes.HasPrefix(start, bomUTF16BE):
+		text.start = start[len(bomUTF16BE):]
+		return &utf16Reader{input: bufio.NewReader(text), order: binary.BigEndian}, encodingUTF16BE
+	}
+
+	text.start = start
+	return text, encodingUTF8
+}
+
+//Return the bytes read while looking for a byte order mark, then the rest of the input
+type startReader struct {
+	buf   [3]byte
+	start []byte //the part of buf not yet returned
+	input io.Reader
+}
+
+func (reader *startReader) Read(p []byte) (int, error) {
+	if len(reader.start) > 0 {
+		n := copy(p, reader.start)
+		reader.start = reader.start[n:]
+		return n, nil
+	}
+
+	return reader.input.Read(p)
+}
+
+//Convert UTF-8 text to the encoding, starting with its byte order mark
+func (encoding textEncoding) encode(text []byte) []byte {
+	var order binary.AppendByteOrder
+
+	switch encoding {
+	case encodingUTF8:
+		return text
+	case encodingUTF8BOM:
+		return append(append([]byte(nil), bomUTF8...), text...)
+	case encodingUTF16LE:
+		order = binary.LittleEndian
+	case encodingUTF16BE:
+		order = binary.BigEndian
+	}
+
+	out := order.AppendUint16(make([]byte, 0, 2*len(text)+2), 0xfeff)
+
+	for len(text) > 0 {
+		r, size := utf8.DecodeRune(text)
+		text = text[size:]
+
+		var units [2]uint16
+
+		for _, unit := range utf16.AppendRune(units[:0], r) {
+			out = order.AppendUint16(out, unit)
+		}
+	}
+
+	return out
+}
+
+//Convert UTF-16 to UTF-8, invalid surrogates and a trailing odd byte become U+FFFD
+type utf16Reader struct {
+	input   *bufio.Reader
+	order   binary.ByteOrder
+	decoded []byte //text converted but not yet read
+	err     error
+}
+
+func (reader *utf16Reader) Read(p []byte) (int, error) {
+	for len(reader.decoded) == 0 {
+		if reader.err != nil {
+			return 0, reader.err
+		}
+
+		reader.fill()
+	}
+
+	n := copy(p, reader.decoded)
+	reader.decoded = reader.decoded[n:]
+	return n, nil
+}
+
+//Convert the next part of the input
+func (reader *utf16Reader) fill() {
+	const units = 512 //converted at a time
+	decoded := reader.decoded[:0]
+
+	for i := 0; i < units && reader.err == nil; i++ {
+		r, ok := reader.unit()
+
+		if !ok {
+			break
+		}
+
+		if utf16.IsSurrogate(r) {
+			//a high surrogate is only used if a low surrogate follows it
+			if next, err := reader.input.Peek(2); err == nil {
+				if pair := utf16.DecodeRune(r, rune(reader.order.Uint16(next))); pair != utf8.RuneError {
+					reader.input.Discard(2)
+					r = pair
+				}
+			}
+
+			if utf16.IsSurrogate(r) {
+				r = utf8.RuneError
+			}
+		}
+
+		decoded = utf8.AppendRune(decoded, r)
+	}
+
+	reader.decoded = decoded
+}
+
+//Read a single code unit, at the end of the input the error is kept and false is returned
+func (reader *utf16Reader) unit() (rune, bool) {
+	var buf [2]byte
+	_, err := io.ReadFull(reader.input, buf[:])
+
+	switch err {
+	case nil:
+		return rune(reader.order.Uint16(buf[:])), true
+	case io.ErrUnexpectedEOF:
+		reader.err = io.EOF
+		return utf8.RuneError, true
+	}
+
+	reader.err = err
+	return 0, false
+}
+
+//Split lines at \n, \r\n or a lone \r, the line ending is left on the line for splitEnding
+func scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	//IndexByte is much faster than IndexAny, so look for \n and then for a \r before it
+	i := bytes.IndexByte(data, '\n')
+	before := data
+
+	if i >= 0 {
+		before = data[:i]
+	}
+
+	if r := bytes.IndexByte(before, '\r'); r >= 0 {
+		i = r
+	}
+
+	if i >= 0 {
+		switch {
+		case data[i] == '\n':
+			return i + 1, data[:i+1], nil
+		case i+1 < len(data) && data[i+1] == '\n':
+			return i + 2, data[:i+2], nil
+		case i+1 < len(data) || atEOF:
+			return i + 1, data[:i+1], nil
+		}
+
+		return 0, nil, nil //wait to see if \n follows the \r
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
+
+//Remove the line ending from a line returned by scanLines, the last line may not have one
+func splitEnding(line []byte) ([]byte, string) {
+	n := len(line)
+
+	switch {
+	case n >= 2 && line[n-2] == '\r' && line[n-1] == '\n':
+		return line[:n-2], "\r\n"
+	case n >= 1 && line[n-1] == '\n':
+		return line[:n-1], "\n"
+	case n >= 1 && line[n-1] == '\r':
+		return line[:n-1], "\r"
+	}
+
+	return line, ""
+}
+
+//Apply the UTF8Policy to a line, returning the line to use
+func (scanner *Scanner) checkUTF8(line []byte) ([]byte, error) {
+	if scanner.invalidUTF8 == UTF8Unchecked || utf8.Valid(line) {
+		return line, nil
+	}
+
+	if scanner.invalidUTF8 == UTF8Replace {
+		return bytes.ToValidUTF8(line, []byte(string(utf8.RuneError))), nil
+	}
+
+	offset := 0
+
+	for offset < len(line) {
+		r, size := utf8.DecodeRune(line[offset:])
+
+		if r == utf8.RuneError && size == 1 {
+			break
+		}
+
+		offset += size
+	}
+
+	return nil, fmt.Errorf("mini: invalid UTF-8 at %s, byte %d", scanner.position(), offset+1)
+}
+
+/*
+SetInvalidUTF8 chooses what the scanner does with lines that aren't valid UTF-8, by default they are returned as they are.
+*/
+func (scanner *Scanner) SetInvalidUTF8(policy UTF8Policy) {
+	scanner.invalidUTF8 = policy
+}
@@ -0,0 +1,91 @@
+package mini
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+//Encode text as UTF-16 with a byte order mark
+func encodeUTF16(text string, order binary.ByteOrder) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, order, uint16(0xfeff))
+	binary.Write(&buf, order, utf16.Encode([]rune(text)))
+	return buf.Bytes()
+}
+
+func TestByteOrderMark(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader("\ufeffname=a\n[section]\nkey=b"))
+	assert.Nil(t, err, "Configuration should load without error.")
+	assert.Equal(t, config.String("name", ""), "a", "Byte order mark should not be part of the first key")
+
+	config, err = LoadConfigurationFromReader(strings.NewReader("\ufeff[section]\nkey=b"))
+	assert.Nil(t, err, "Byte order mark should not break the first section header")
+	assert.Equal(t, config.StringFromSection("section", "key", ""), "b", "Read value after byte order mark wrong")
+}
+
+func TestUTF16(t *testing.T) {
+
+	ini := "name=café\r\n[section]\r\nemoji=\U0001F600\r\n"
+
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		config, err := LoadConfigurationFromReader(bytes.NewReader(encodeUTF16(ini, order)))
+
+		assert.Nil(t, err, "UTF-16 configuration should load without error: "+order.String())
+		assert.Equal(t, config.String("name", ""), "café", "Read UTF-16 value wrong: "+order.String())
+		assert.Equal(t, config.StringFromSection("section", "emoji", ""), "\U0001F600", "Surrogate pair should be decoded: "+order.String())
+	}
+
+	bad := append(encodeUTF16("a=", binary.LittleEndian), 0x00, 0xd8, 'b', 0) //unpaired high surrogate
+	config, err := LoadConfigurationFromReader(bytes.NewReader(bad))
+	assert.Nil(t, err, "Unpaired surrogate should not be an error")
+	assert.Equal(t, config.String("a", ""), "\ufffdb", "Unpaired surrogate should be replaced")
+}
+
+func TestLineEndings(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader("a=1\r\nb=2\rc=3\n\r\nd=4\r"))
+	assert.Nil(t, err, "Configuration should load without error.")
+	assert.Equal(t, config.String("a", ""), "1", "CRLF should end a line")
+	assert.Equal(t, config.String("b", ""), "2", "CR should end a line")
+	assert.Equal(t, config.String("c", ""), "3", "LF should end a line")
+	assert.Equal(t, config.String("d", ""), "4", "CR at the end should be removed")
+
+	origin, _ := config.Origin("", "d")
+	assert.Equal(t, origin.Pos.Line, 5, "Lines should be counted once for CRLF")
+
+	doc, err := ParseDocument(strings.NewReader("a = 1 \r\n[s]\r\n"))
+	assert.Nil(t, err, "ParseDocument should not return an error.")
+
+	var buf bytes.Buffer
+	doc.WriteTo(&buf)
+	assert.Equal(t, buf.String(), "a = 1 \r\n[s]\r\n", "Documents should keep their line endings")
+}
+
+func TestInvalidUTF8(t *testing.T) {
+
+	ini := "a=1\nb=x\xffy\n"
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(ini))
+	assert.Nil(t, err, "Invalid UTF-8 should be read by default")
+	assert.Equal(t, config.String("b", ""), "x\xffy", "Invalid bytes should be kept by default")
+
+	config = &Config{Options: Options{InvalidUTF8: UTF8Reject}}
+	err = config.InitializeFromReader(strings.NewReader(ini))
+	assert.NotNil(t, err, "Invalid UTF-8 should be rejected")
+	assert.Equal(t, err.Error(), "mini: invalid UTF-8 at line 2, byte 4", "Error should have its position")
+
+	config = &Config{Options: Options{InvalidUTF8: UTF8Replace}}
+	assert.Nil(t, config.InitializeFromReader(strings.NewReader(ini)), "Replaced UTF-8 should load")
+	assert.Equal(t, config.String("b", ""), "x\ufffdy", "Invalid bytes should be replaced")
+
+	scanner := NewScanner(strings.NewReader(ini))
+	scanner.SetInvalidUTF8(UTF8Replace)
+	scanner.Next()
+	token, _ := scanner.Next()
+	assert.Equal(t, token.Raw, "b=x\ufffdy", "Scanner should replace invalid bytes in the raw line")
+}
@@ -0,0 +1,379 @@
+/*
+Mini reads and edits ini files from the command line.
+
+Usage:
+
+	mini [-f file] [-k keyfile] command [arguments]
+
+The commands are:
+
+	get section.key
+		print a value, array values are printed one per line and map values as name=value
+	set section.key value
+		set a value, keeping the comments and layout of the rest of the file
+	del section.key
+		remove a key, including its array or map entries
+	sections
+		list the section names
+	keys [section]
+		list the keys in a section, or the global keys
+	dump [--json]
+		print every value as section.key=value, or as a JSON object, secrets are redacted
+	encrypt section.key ...
+		encrypt the values of the keys with the -k key, leaving the rest of the file as it was
+	keygen
+		print a new key for -k
+
+A key without a section, such as "name", is a global key. The section is everything before the last dot,
+so "server.http.port" is the key port in the section server.http.
+
+Without -f the file is read from standard input, and set and del write the result to standard output.
+Get exits with status 1 if the key is missing, and del if there was nothing to remove.
+
+Encrypted values, written as ENC[AES256_GCM,...], are decrypted with the key in the -k file when it is set.
+Commit the encrypted file and keep the key file out of version control.
+*/
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fogcreek/mini"
+)
+
+var (
+	file    = flag.String("f", "", "the ini file, standard input if not set")
+	keyPath = flag.String("k", "", "the key file used to encrypt and decrypt values")
+)
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "mini:", err)
+
+		if errors.Is(err, errMissing) {
+			os.Exit(1)
+		}
+
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: mini [-f file] [-k keyfile] get|set|del|sections|keys|dump|encrypt|keygen [arguments]")
+	flag.PrintDefaults()
+}
+
+var errMissing = errors.New("not found")
+
+func run(command string, args []string) error {
+
+	if command == "keygen" {
+		key, err := mini.NewKey()
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(key)
+		return nil
+	}
+
+	input, err := readInput()
+
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "get":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: get section.key")
+		}
+		return get(input, args[0])
+	case "set":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: set section.key value")
+		}
+		return edit(input, func(doc *mini.Document) error {
+			section, key := splitPath(args[0])
+			doc.Set(section, key, args[1])
+			return nil
+		})
+	case "del":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: del section.key")
+		}
+		return edit(input, func(doc *mini.Document) error {
+			if !doc.Delete(splitPath(args[0])) {
+				return errMissing
+			}
+			return nil
+		})
+	case "sections":
+		config, err := loadConfig(input)
+
+		if err != nil {
+			return err
+		}
+
+		printLines(config.SectionNames())
+		return nil
+	case "keys":
+		config, err := loadConfig(input)
+
+		if err != nil {
+			return err
+		}
+
+		if len(args) > 0 {
+			printLines(config.KeysForSection(args[0]))
+		} else {
+			printLines(config.Keys())
+		}
+		return nil
+	case "dump":
+		flags := flag.NewFlagSet("dump", flag.ContinueOnError)
+		asJSON := flags.Bool("json", false, "print a JSON object")
+
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		return dump(input, *asJSON)
+	case "encrypt":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: encrypt section.key ...")
+		}
+
+		keyFile, err := loadKeyFile()
+
+		if err != nil {
+			return err
+		}
+
+		return edit(input, func(doc *mini.Document) error {
+			for _, path := range args {
+				section, key := splitPath(path)
+				found, err := doc.Encrypt(section, key, keyFile)
+
+				if err != nil {
+					return err
+				}
+
+				if !found {
+					return fmt.Errorf("%s: %w", path, errMissing)
+				}
+			}
+			return nil
+		})
+	}
+
+	return fmt.Errorf("unknown command %q", command)
+}
+
+func readInput() ([]byte, error) {
+	if len(*file) == 0 {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(*file)
+}
+
+func loadKeyFile() (*mini.KeyFile, error) {
+	if len(*keyPath) == 0 {
+		return nil, errors.New("a key file is needed, set it with -k")
+	}
+	return mini.LoadKeyFile(*keyPath)
+}
+
+//Load the config, decrypting values if there is a key file
+func loadConfig(input []byte) (*mini.Config, error) {
+
+	config := &mini.Config{}
+
+	if len(*keyPath) > 0 {
+		keyFile, err := mini.LoadKeyFile(*keyPath)
+
+		if err != nil {
+			return nil, err
+		}
+
+		config.Options.Decrypter = keyFile
+	}
+
+	if err := config.InitializeFromReader(bytes.NewReader(input)); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+//Return the text of a value, including secrets, which String redacts
+func text(val mini.Value) string {
+	if val.Secret() {
+		if text, err := val.Text(); err == nil {
+			return text
+		}
+	}
+	return val.String()
+}
+
+//Split section.key at the last dot
+func splitPath(path string) (string, string) {
+	if dot := strings.LastIndexByte(path, '.'); dot >= 0 {
+		return path[:dot], path[dot+1:]
+	}
+	return "", path
+}
+
+func printLines(lines []string) {
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+}
+
+func get(input []byte, path string) error {
+
+	config, err := loadConfig(input)
+
+	if err != nil {
+		return err
+	}
+
+	val, ok := config.Lookup(splitPath(path))
+
+	if !ok {
+		return errMissing
+	}
+
+	switch val.Kind() {
+	case mini.ArrayValue:
+		for i := 0; i < val.Len(); i++ {
+			fmt.Println(text(val.Index(i)))
+		}
+	case mini.MapValue:
+		for _, name := range val.Names() {
+			entry, _ := val.Get(name)
+			fmt.Println(name + "=" + text(entry))
+		}
+	default:
+		fmt.Println(text(val))
+	}
+
+	return nil
+}
+
+//Change the document and write it back to the file, or to standard output
+func edit(input []byte, change func(doc *mini.Document) error) error {
+
+	doc, err := mini.ParseDocument(bytes.NewReader(input))
+
+	if err != nil {
+		return err
+	}
+
+	if err := change(doc); err != nil {
+		return err
+	}
+
+	var output bytes.Buffer
+
+	if _, err := doc.WriteTo(&output); err != nil {
+		return err
+	}
+
+	if len(*file) == 0 {
+		_, err = os.Stdout.Write(output.Bytes())
+		return err
+	}
+
+	info, err := os.Stat(*file)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(*file, output.Bytes(), info.Mode().Perm())
+}
+
+//Return the value as a string, a list of strings or a map of strings
+func plain(val mini.Value) interface{} {
+	switch val.Kind() {
+	case mini.ArrayValue:
+		list := make([]string, val.Len())
+		for i := range list {
+			list[i] = val.Index(i).String()
+		}
+		return list
+	case mini.MapValue:
+		m := make(map[string]string)
+		for _, name := range val.Names() {
+			entry, _ := val.Get(name)
+			m[name] = entry.String()
+		}
+		return m
+	}
+	return val.String()
+}
+
+func dump(input []byte, asJSON bool) error {
+
+	config, err := loadConfig(input)
+
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		m, err := config.ToMap()
+
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(m)
+	}
+
+	printValues := func(prefix string, keys []string, section string) {
+		for _, key := range keys {
+			val, _ := config.Lookup(section, key)
+
+			switch p := plain(val).(type) {
+			case []string:
+				for _, entry := range p {
+					fmt.Printf("%s%s[]=%s\n", prefix, key, entry)
+				}
+			case map[string]string:
+				for _, name := range val.Names() {
+					fmt.Printf("%s%s[%s]=%s\n", prefix, key, name, p[name])
+				}
+			default:
+				fmt.Printf("%s%s=%s\n", prefix, key, p)
+			}
+		}
+	}
+
+	printValues("", config.KeysInOrder(""), "")
+
+	for _, section := range config.SectionsInOrder() {
+		printValues(section+".", config.KeysInOrder(section), section)
+	}
+
+	return nil
+}
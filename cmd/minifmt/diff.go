@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//The number of unchanged lines shown around each change
+const context = 3
+
+//A run of n lines that are the same in both files, starting at line x of the first and line y of the second
+type match struct {
+	x, y, n int
+}
+
+//A line of the diff, op is ' ', '-' or '+', x and y are the number of lines of each file before it
+type diffLine struct {
+	op   byte
+	text string
+	x, y int
+}
+
+//Return a unified diff of a and b, with a hunk for each group of changes and up to 3 lines of context around them
+func unifiedDiff(path string, a string, b string) string {
+
+	before := splitLines(a)
+	after := splitLines(b)
+	lines := diffLines(before, after)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s.orig\n+++ %s\n", path, path)
+
+	for start := 0; start < len(lines); {
+		//find the next change and the last change within reach of its context
+		first := start
+		for first < len(lines) && lines[first].op == ' ' {
+			first++
+		}
+
+		if first == len(lines) {
+			break
+		}
+
+		last := first
+		for i := first; i < len(lines) && i <= last+2*context; i++ {
+			if lines[i].op != ' ' {
+				last = i
+			}
+		}
+
+		from := max(first-context, start)
+		to := min(last+context+1, len(lines))
+		writeHunk(&out, lines[from:to])
+		start = to
+	}
+
+	return out.String()
+}
+
+//Split text into lines that keep their \n
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+//Write a hunk header followed by its lines
+func writeHunk(out *strings.Builder, hunk []diffLine) {
+	removed, added := 0, 0
+
+	for _, line := range hunk {
+		if line.op != '+' {
+			removed++
+		}
+		if line.op != '-' {
+			added++
+		}
+	}
+
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(hunk[0].x, removed), hunkRange(hunk[0].y, added))
+
+	for _, line := range hunk {
+		out.WriteByte(line.op)
+		out.WriteString(line.text)
+
+		if !strings.HasSuffix(line.text, "\n") {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+//Return the start and length of a hunk in one file, an empty range starts at the line before it
+func hunkRange(before int, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, n)
+}
+
+//Return every line of the diff between x and y, unchanged lines included
+func diffLines(x []string, y []string) []diffLine {
+	var lines []diffLine
+	px, py := 0, 0
+
+	for _, m := range matches(x, y) {
+		for ; px < m.x; px++ {
+			lines = append(lines, diffLine{'-', x[px], px, py})
+		}
+
+		for ; py < m.y; py++ {
+			lines = append(lines, diffLine{'+', y[py], px, py})
+		}
+
+		for i := 0; i < m.n; i++ {
+			lines = append(lines, diffLine{' ', x[px], px, py})
+			px++
+			py++
+		}
+	}
+
+	return lines
+}
+
+/*
+Return the runs of lines shared by x and y in order, the last run ends at the end of both. As in patience diff,
+lines that appear exactly once in each file are matched first, then each match is extended to the lines around it.
+*/
+func matches(x []string, y []string) []match {
+	countX := make(map[string]int)
+	countY := make(map[string]int)
+	lineY := make(map[string]int)
+
+	for _, line := range x {
+		countX[line]++
+	}
+
+	for j, line := range y {
+		countY[line]++
+		lineY[line] = j
+	}
+
+	var anchors []match
+
+	for i, line := range x {
+		if countX[line] == 1 && countY[line] == 1 {
+			anchors = append(anchors, match{i, lineY[line], 1})
+		}
+	}
+
+	anchors = append(inOrder(anchors), match{len(x), len(y), 0})
+
+	var runs []match
+	px, py := 0, 0
+
+	for _, anchor := range anchors {
+		if anchor.x < px || anchor.y < py { //already part of the last run
+			continue
+		}
+
+		n := 0
+		for px+n < anchor.x && py+n < anchor.y && x[px+n] == y[py+n] {
+			n++
+		}
+
+		if n > 0 {
+			runs = append(runs, match{px, py, n})
+		}
+
+		startX, startY := anchor.x, anchor.y
+		for startX > px+n && startY > py+n && x[startX-1] == y[startY-1] {
+			startX--
+			startY--
+		}
+
+		endX, endY := anchor.x+anchor.n, anchor.y+anchor.n
+		for endX < len(x) && endY < len(y) && x[endX] == y[endY] {
+			endX++
+			endY++
+		}
+
+		runs = append(runs, match{startX, startY, endX - startX})
+		px, py = endX, endY
+	}
+
+	return runs
+}
+
+//Return the longest list of anchors, which are sorted by x, whose y values also increase
+func inOrder(anchors []match) []match {
+	var tails []int //tails[n] is the anchor ending the best list of length n+1 found so far
+	previous := make([]int, len(anchors))
+
+	for i, anchor := range anchors {
+		n := sort.Search(len(tails), func(k int) bool { return anchors[tails[k]].y >= anchor.y })
+
+		previous[i] = -1
+		if n > 0 {
+			previous[i] = tails[n-1]
+		}
+
+		if n == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[n] = i
+		}
+	}
+
+	list := make([]match, len(tails))
+
+	if len(tails) > 0 {
+		for i, k := len(tails)-1, tails[len(tails)-1]; i >= 0; i, k = i-1, previous[k] {
+			list[i] = anchors[k]
+		}
+	}
+
+	return list
+}
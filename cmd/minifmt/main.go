@@ -0,0 +1,219 @@
+/*
+Minifmt formats ini files.
+
+Usage:
+
+	minifmt [flags] [path ...]
+
+Without a path it formats standard input to standard output. A directory is searched for .ini files.
+
+The flags are:
+
+	-l
+		list files whose formatting differs from minifmt's
+	-d
+		print a diff instead of the formatted file
+	-w
+		write the result back to the file instead of standard output
+	-spaces
+		write key = value rather than key=value (default true)
+	-indent string
+		indentation for the keys in a section
+	-keys as-written|lower|first
+		how keys are spelled
+	-quotes as-written|needed|double
+		how quoted values are written
+	-sort
+		sort the keys in each section
+	-merge
+		merge split sections
+	-secret patterns
+		comma separated keys, like password,db.*token*, whose values are redacted in diffs
+*/
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fogcreek/mini"
+)
+
+var (
+	list   = flag.Bool("l", false, "list files whose formatting differs from minifmt's")
+	diff   = flag.Bool("d", false, "print a diff instead of the formatted file")
+	write  = flag.Bool("w", false, "write the result back to the file instead of standard output")
+	spaces = flag.Bool("spaces", true, "write key = value rather than key=value")
+	indent = flag.String("indent", "", "indentation for the keys in a section")
+	keys   = flag.String("keys", "as-written", "how keys are spelled: as-written, lower or first")
+	quotes = flag.String("quotes", "as-written", "how quoted values are written: as-written, needed or double")
+	sorted = flag.Bool("sort", false, "sort the keys in each section")
+	merge  = flag.Bool("merge", false, "merge split sections")
+	secret = flag.String("secret", "", "comma separated keys whose values are redacted in diffs")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: minifmt [flags] [path ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	options, err := formatOptions()
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "minifmt:", err)
+		os.Exit(2)
+	}
+
+	if flag.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "minifmt: cannot use -w with standard input")
+			os.Exit(2)
+		}
+
+		if err := processFile("<standard input>", os.Stdin, options); err != nil {
+			fmt.Fprintln(os.Stderr, "minifmt:", err)
+			os.Exit(2)
+		}
+
+		return
+	}
+
+	failed := false
+
+	for _, root := range flag.Args() {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			//files named on the command line are formatted whatever their extension
+			if entry.IsDir() || (path != root && filepath.Ext(path) != ".ini") {
+				return nil
+			}
+
+			f, err := os.Open(path)
+
+			if err != nil {
+				return err
+			}
+
+			defer f.Close()
+
+			return processFile(path, f, options)
+		})
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "minifmt:", err)
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(2)
+	}
+}
+
+func formatOptions() (mini.FormatOptions, error) {
+
+	options := mini.FormatOptions{
+		SpaceAroundEquals: *spaces,
+		Indent:            *indent,
+		SortKeys:          *sorted,
+		MergeSections:     *merge,
+	}
+
+	switch *keys {
+	case "as-written":
+		options.KeyCase = mini.KeysAsWritten
+	case "lower":
+		options.KeyCase = mini.KeysLower
+	case "first":
+		options.KeyCase = mini.KeysFirstSpelling
+	default:
+		return options, fmt.Errorf("unknown -keys %q", *keys)
+	}
+
+	switch *quotes {
+	case "as-written":
+		options.Quotes = mini.QuoteAsWritten
+	case "needed":
+		options.Quotes = mini.QuoteWhenNeeded
+	case "double":
+		options.Quotes = mini.QuoteDouble
+	default:
+		return options, fmt.Errorf("unknown -quotes %q", *quotes)
+	}
+
+	return options, nil
+}
+
+func processFile(path string, input io.Reader, options mini.FormatOptions) error {
+
+	src, err := io.ReadAll(input)
+
+	if err != nil {
+		return err
+	}
+
+	var res bytes.Buffer
+
+	if err := mini.Format(bytes.NewReader(src), &res, options); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	if !*list && !*diff && !*write {
+		_, err = os.Stdout.Write(res.Bytes())
+		return err
+	}
+
+	if bytes.Equal(src, res.Bytes()) {
+		return nil
+	}
+
+	if *list {
+		fmt.Println(path)
+	}
+
+	if *diff {
+		os.Stdout.WriteString(unifiedDiff(path, redact(src), redact(res.Bytes())))
+	}
+
+	if *write {
+		info, err := os.Stat(path)
+
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(path, res.Bytes(), info.Mode().Perm())
+	}
+
+	return nil
+}
+
+//Return the file with the values of the -secret keys redacted
+func redact(src []byte) string {
+	if len(*secret) == 0 {
+		return string(src)
+	}
+
+	doc, err := mini.ParseDocument(bytes.NewReader(src))
+
+	if err != nil {
+		return string(src)
+	}
+
+	doc.Redact(strings.Split(*secret, ","))
+
+	var out strings.Builder
+	doc.WriteTo(&out)
+	return out.String()
+}
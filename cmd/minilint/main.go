@@ -0,0 +1,213 @@
+/*
+Minilint checks ini files for common mistakes.
+
+Usage:
+
+	minilint [flags] [path ...]
+
+Without a path it checks standard input. A directory is searched for .ini files.
+Minilint exits with status 1 if it finds any problems.
+
+The flags are:
+
+	-format text|json|sarif
+		how problems are printed, sarif is SARIF 2.1.0 for code scanning tools
+	-schema file
+		an ini file holding every allowed section and key, other sections and keys are reported
+	-disable rules
+		comma separated names of rules to skip
+	-enable rules
+		comma separated names of optional rules to run, like unindented-key
+	-rules
+		list the rules and exit
+*/
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fogcreek/mini"
+)
+
+var (
+	format    = flag.String("format", "text", "how problems are printed: text, json or sarif")
+	schema    = flag.String("schema", "", "an ini file holding every allowed section and key")
+	disable   = flag.String("disable", "", "comma separated names of rules to skip")
+	enable    = flag.String("enable", "", "comma separated names of optional rules to run")
+	listRules = flag.Bool("rules", false, "list the rules and exit")
+)
+
+//A problem in a file, in the form written by -format json
+type problem struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: minilint [flags] [path ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	rules, err := selectRules()
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "minilint:", err)
+		os.Exit(2)
+	}
+
+	if *listRules {
+		for _, rule := range rules {
+			fmt.Printf("%-22s %-8s %s\n", rule.Name, rule.Severity, rule.Description)
+		}
+		return
+	}
+
+	var problems []problem
+
+	check := func(path string, input io.Reader) {
+		for _, d := range mini.Lint(input, rules...) {
+			problems = append(problems, problem{File: path, Line: d.Pos.Line, Rule: d.Rule, Severity: d.Severity.String(), Message: d.Message})
+		}
+	}
+
+	if flag.NArg() == 0 {
+		check("<standard input>", os.Stdin)
+	}
+
+	for _, root := range flag.Args() {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			//files named on the command line are checked whatever their extension
+			if entry.IsDir() || (path != root && filepath.Ext(path) != ".ini") {
+				return nil
+			}
+
+			f, err := os.Open(path)
+
+			if err != nil {
+				return err
+			}
+
+			defer f.Close()
+
+			check(path, f)
+			return nil
+		})
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "minilint:", err)
+			os.Exit(2)
+		}
+	}
+
+	if err := write(os.Stdout, problems, rules); err != nil {
+		fmt.Fprintln(os.Stderr, "minilint:", err)
+		os.Exit(2)
+	}
+
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+}
+
+//Return the rules chosen by -schema, -enable and -disable
+func selectRules() ([]mini.Rule, error) {
+
+	rules := mini.DefaultRules()
+
+	if len(*enable) > 0 {
+		optional := make(map[string]mini.Rule)
+
+		for _, rule := range mini.OptionalRules() {
+			optional[rule.Name] = rule
+		}
+
+		for _, name := range strings.Split(*enable, ",") {
+			name = strings.TrimSpace(name)
+			rule, ok := optional[name]
+
+			if !ok {
+				return nil, fmt.Errorf("unknown optional rule %q", name)
+			}
+
+			rules = append(rules, rule)
+		}
+	}
+
+	if len(*schema) > 0 {
+		config, err := mini.LoadConfiguration(*schema)
+
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, mini.RuleUnknownKeys(config))
+	}
+
+	if len(*disable) == 0 {
+		return rules, nil
+	}
+
+	skip := make(map[string]bool)
+
+	for _, name := range strings.Split(*disable, ",") {
+		skip[strings.TrimSpace(name)] = true
+	}
+
+	selected := rules[:0]
+
+	for _, rule := range rules {
+		if skip[rule.Name] {
+			delete(skip, rule.Name)
+			continue
+		}
+		selected = append(selected, rule)
+	}
+
+	for name := range skip {
+		return nil, fmt.Errorf("unknown rule %q", name)
+	}
+
+	return selected, nil
+}
+
+func write(output io.Writer, problems []problem, rules []mini.Rule) error {
+	switch *format {
+	case "text":
+		for _, p := range problems {
+			fmt.Fprintf(output, "%s:%d: %s: %s (%s)\n", p.File, p.Line, p.Severity, p.Message, p.Rule)
+		}
+		return nil
+	case "json":
+		if problems == nil {
+			problems = []problem{}
+		}
+
+		encoder := json.NewEncoder(output)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(problems)
+	case "sarif":
+		encoder := json.NewEncoder(output)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(sarifLog(problems, rules))
+	}
+
+	return fmt.Errorf("unknown -format %q", *format)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/fogcreek/mini"
+)
+
+//The parts of SARIF 2.1.0 that minilint writes
+
+type sarif struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           sarifRegion   `json:"region"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func sarifLog(problems []problem, rules []mini.Rule) sarif {
+
+	driver := sarifDriver{Name: "minilint", Rules: []sarifRule{{ID: "syntax", ShortDescription: sarifMessage{"a line isn't valid ini"}}}}
+
+	for _, rule := range rules {
+		driver.Rules = append(driver.Rules, sarifRule{ID: rule.Name, ShortDescription: sarifMessage{rule.Description}})
+	}
+
+	results := make([]sarifResult, 0, len(problems))
+
+	for _, p := range problems {
+		line := p.Line
+
+		if line < 1 {
+			line = 1
+		}
+
+		results = append(results, sarifResult{
+			RuleID:  p.Rule,
+			Level:   p.Severity,
+			Message: sarifMessage{p.Message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifact{URI: filepath.ToSlash(p.File)},
+				Region:           sarifRegion{StartLine: line},
+			}}},
+		})
+	}
+
+	return sarif{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
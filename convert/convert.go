@@ -0,0 +1,124 @@
+/*
+Package convert translates ini configurations to and from JSON, YAML and TOML.
+
+Configs are converted through Config.ToMap and Config.FromMap, so sections become objects or tables,
+array values become arrays of strings and map values become objects of strings. Importing flattens
+nested objects into dotted sections and returns an error for structures ini can't hold, like arrays of tables.
+Because of that, a map value read back from one of these formats becomes a nested section.
+
+The package is a module of its own, so programs that only import mini don't depend on the YAML and TOML packages.
+*/
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/BurntSushi/toml"
+	"github.com/fogcreek/mini"
+	"gopkg.in/yaml.v3"
+)
+
+/*
+ToJSON returns the config as an indented JSON object.
+*/
+func ToJSON(config *mini.Config) ([]byte, error) {
+
+	m, err := config.ToMap()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(m, "", "  ")
+}
+
+/*
+FromJSON reads a config from a JSON object. Numbers are kept as they were written.
+*/
+func FromJSON(data []byte) (*mini.Config, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var m map[string]interface{}
+
+	if err := decoder.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return fromMap(m)
+}
+
+/*
+ToYAML returns the config as a YAML document.
+*/
+func ToYAML(config *mini.Config) ([]byte, error) {
+
+	m, err := config.ToMap()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(m)
+}
+
+/*
+FromYAML reads a config from a YAML document.
+*/
+func FromYAML(data []byte) (*mini.Config, error) {
+
+	var m map[string]interface{}
+
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	return fromMap(m)
+}
+
+/*
+ToTOML returns the config as a TOML document.
+*/
+func ToTOML(config *mini.Config) ([]byte, error) {
+
+	m, err := config.ToMap()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	if err := toml.NewEncoder(&buf).Encode(m); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+/*
+FromTOML reads a config from a TOML document.
+*/
+func FromTOML(data []byte) (*mini.Config, error) {
+
+	var m map[string]interface{}
+
+	if err := toml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	return fromMap(m)
+}
+
+func fromMap(m map[string]interface{}) (*mini.Config, error) {
+
+	config := new(mini.Config)
+
+	if err := config.FromMap(m); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
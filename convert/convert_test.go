@@ -0,0 +1,74 @@
+package convert
+
+import (
+	"github.com/fogcreek/mini"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const convertIni = `name=test
+hosts[]=a
+hosts[]=b
+
+[server.http]
+port=80`
+
+func load(t *testing.T) *mini.Config {
+	config, err := mini.LoadConfigurationFromReader(strings.NewReader(convertIni))
+	assert.Nil(t, err, "Configuration should load without error.")
+	return config
+}
+
+func toMap(t *testing.T, config *mini.Config) map[string]interface{} {
+	m, err := config.ToMap()
+	assert.Nil(t, err, "ToMap should not return an error.")
+	return m
+}
+
+func TestJSON(t *testing.T) {
+
+	data, err := ToJSON(load(t))
+	assert.Nil(t, err, "ToJSON should not return an error.")
+
+	config, err := FromJSON(data)
+	assert.Nil(t, err, "FromJSON should not return an error.")
+	assert.Equal(t, toMap(t, config), toMap(t, load(t)), "JSON round trip should keep every value")
+
+	config, err = FromJSON([]byte(`{"server": {"http": {"port": 12345678901234567890}}}`))
+	assert.Nil(t, err, "FromJSON should not return an error.")
+	assert.Equal(t, config.StringFromSection("server.http", "port", ""), "12345678901234567890", "Numbers should be kept as written")
+}
+
+func TestYAML(t *testing.T) {
+
+	data, err := ToYAML(load(t))
+	assert.Nil(t, err, "ToYAML should not return an error.")
+
+	config, err := FromYAML(data)
+	assert.Nil(t, err, "FromYAML should not return an error.")
+	assert.Equal(t, toMap(t, config), toMap(t, load(t)), "YAML round trip should keep every value")
+
+	config, err = FromYAML([]byte("server:\n  http:\n    port: 80\n    hosts: [a, b]\n"))
+	assert.Nil(t, err, "FromYAML should not return an error.")
+	assert.Equal(t, config.IntegerFromSection("server.http", "port", 0), int64(80), "Read port wrong")
+	assert.Equal(t, config.StringsFromSection("server.http", "hosts"), []string{"a", "b"}, "Read hosts wrong")
+}
+
+func TestTOML(t *testing.T) {
+
+	data, err := ToTOML(load(t))
+	assert.Nil(t, err, "ToTOML should not return an error.")
+
+	config, err := FromTOML(data)
+	assert.Nil(t, err, "FromTOML should not return an error.")
+	assert.Equal(t, toMap(t, config), toMap(t, load(t)), "TOML round trip should keep every value")
+
+	config, err = FromTOML([]byte("[server]\nheaders = { Accept = \"text/html\" }\n"))
+	assert.Nil(t, err, "FromTOML should not return an error.")
+	assert.Equal(t, config.StringFromSection("server.headers", "Accept", ""), "text/html", "Inline tables should be nested sections")
+
+	_, err = FromTOML([]byte("[[rules]]\nname = \"a\"\n"))
+	assert.NotNil(t, err, "Arrays of tables should be an error.")
+	assert.True(t, strings.Contains(err.Error(), "arrays of tables"), "Error should explain the problem")
+}
@@ -0,0 +1,18 @@
+module github.com/fogcreek/mini/convert
+
+go 1.22
+
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/fogcreek/mini v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.8.4
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+)
+
+//convert is versioned with mini, so it is built against the mini next to it
+replace github.com/fogcreek/mini => ../
@@ -0,0 +1,129 @@
+package mini
+
+import (
+	"fmt"
+	"reflect"
+)
+
+/*
+Defaults sets the fields of the struct pointed to by v from their default tags, as in `default:"30s"`.
+Only fields holding their zero value are set, and struct fields read as sections are filled in the same way,
+allocating nil pointers to structs that have defaults, except pointers back to a struct already being filled. Defaults are read with the same rules as Decode,
+and a default that can't be read is returned as an UnmarshalTypeError.
+*/
+func Defaults(v interface{}) error {
+
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("mini: Defaults needs a non-nil pointer to a struct, not %T", v)
+	}
+
+	state := &decodeState{dec: &Decoder{}, config: &Config{}, decoded: make(map[*configSection]bool)}
+
+	if err := state.applyDefaults(rv.Elem(), ""); err != nil {
+		return err
+	}
+
+	return state.err
+}
+
+func (state *decodeState) applyDefaults(v reflect.Value, sectionName string) error {
+
+	defer state.enter(v.Type())()
+
+	for _, field := range structFields(v.Type()) {
+		fv := v.FieldByIndex(field.index)
+
+		if isSectionType(fv.Type()) {
+			if !hasDefaults(fv.Type(), nil) || state.cycles(fv) {
+				continue
+			}
+
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+				fv = fv.Elem()
+			}
+
+			childName := field.name
+
+			if len(sectionName) > 0 {
+				childName = sectionName + "." + field.name
+			}
+
+			if err := state.applyDefaults(fv, childName); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !field.hasDef || !fv.IsZero() {
+			continue
+		}
+
+		val := defaultValue(field.def, &state.config.Options)
+
+		if err := state.keepTypeError(state.decodeValue(val, field, fv, sectionName)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//Read a default tag like a value in the file, with inline lists always on so a default can fill a slice
+func defaultValue(def string, options *Options) *value {
+	withLists := *options
+	withLists.InlineLists = true
+	return newValue(def, 0, &source{options: &withLists})
+}
+
+//Return true if a struct, or a struct it holds as a section, has a field with a default tag
+func hasDefaults(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if seen[t] {
+		return false
+	}
+
+	if seen == nil {
+		seen = make(map[reflect.Type]bool)
+	}
+
+	seen[t] = true
+
+	for _, field := range structFields(t) {
+		ft := t.FieldByIndex(field.index).Type
+
+		if field.hasDef || (isSectionType(ft) && hasDefaults(ft, seen)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Set a field read by DataFromSection from its default tag, if it has one and the key is missing
+func (config *Config) defaultField(section *configSection, sectionName string, fieldType reflect.StructField, field reflect.Value) {
+
+	def, ok := fieldType.Tag.Lookup("default")
+
+	if !ok {
+		return
+	}
+
+	if _, found := section.lookup(fieldType.Name); found {
+		return
+	}
+
+	info := structField{name: fieldType.Name, def: def, hasDef: true}
+	info.sep, info.hasSep = fieldType.Tag.Lookup("sep")
+
+	state := &decodeState{dec: &Decoder{}, config: config, decoded: make(map[*configSection]bool)}
+	//DataFromSection skips values it can't read, so a bad default is skipped too
+	state.decodeValue(defaultValue(def, &config.Options), info, field, sectionName)
+}
@@ -0,0 +1,111 @@
+package mini
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+type poolDefaults struct {
+	Size int `default:"4"`
+}
+
+type serviceDefaults struct {
+	Timeout time.Duration `default:"30s"`
+	Retries int           `default:"3"`
+	Hosts   []string      `default:"[a, b]"`
+	Ports   []int         `default:"80,443" sep:","`
+	Name    string
+	Pool    *poolDefaults
+}
+
+func TestDecodeDefaults(t *testing.T) {
+
+	var settings serviceDefaults
+	err := Unmarshal([]byte("retries=5\n[pool]\n"), &settings)
+
+	assert.Nil(t, err, "Unmarshal should not return an error.")
+	assert.Equal(t, settings.Timeout, 30*time.Second, "Missing key should use the default")
+	assert.Equal(t, settings.Retries, 5, "Key in the file should replace the default")
+	assert.Equal(t, settings.Hosts, []string{"a", "b"}, "Inline array default should fill a slice")
+	assert.Equal(t, settings.Ports, []int{80, 443}, "Default should be split with sep")
+	assert.Equal(t, settings.Pool.Size, 4, "Section struct should use its defaults")
+
+	var missing struct {
+		Service serviceDefaults
+	}
+	err = Unmarshal([]byte(""), &missing)
+
+	assert.Nil(t, err, "Unmarshal should not return an error.")
+	assert.Equal(t, missing.Service.Retries, 3, "Missing section should be filled with defaults")
+	assert.Equal(t, missing.Service.Pool.Size, 4, "Missing nested section should be filled with defaults")
+}
+
+func TestDecodeBadDefault(t *testing.T) {
+
+	var settings struct {
+		Count int `default:"many"`
+	}
+
+	err := Unmarshal([]byte(""), &settings)
+
+	var typeErr *UnmarshalTypeError
+	assert.True(t, errors.As(err, &typeErr), "Bad default should be an UnmarshalTypeError")
+	assert.Equal(t, typeErr.Value, "many", "Error should hold the default")
+}
+
+func TestDefaultsFromTags(t *testing.T) {
+
+	settings := serviceDefaults{Retries: 7}
+	err := Defaults(&settings)
+
+	assert.Nil(t, err, "Defaults should not return an error.")
+	assert.Equal(t, settings.Timeout, 30*time.Second, "Zero field should be set")
+	assert.Equal(t, settings.Retries, 7, "Field that is already set should be kept")
+	assert.Equal(t, settings.Name, "", "Field without a default should be left alone")
+	assert.Equal(t, settings.Pool.Size, 4, "Nil section pointer with defaults should be allocated")
+
+	assert.NotNil(t, Defaults(settings), "Defaults should need a pointer")
+}
+
+func TestDataFromSectionDefaults(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader("[s]\nretries=5"))
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	var settings struct {
+		Timeout time.Duration `default:"30s"`
+		Retries int64         `default:"3"`
+		Debug   bool          `default:"true"`
+	}
+
+	assert.True(t, config.DataFromSection("s", &settings), "Section should be found")
+	assert.Equal(t, settings.Timeout, 30*time.Second, "Missing key should use the default")
+	assert.Equal(t, settings.Retries, int64(5), "Key in the file should replace the default")
+	assert.Equal(t, settings.Debug, true, "Missing boolean should use the default")
+}
+
+type listNode struct {
+	X    int `default:"1"`
+	Next *listNode
+}
+
+func TestRecursiveDefaults(t *testing.T) {
+
+	var node listNode
+	err := Unmarshal([]byte("[Next]\nX=2"), &node)
+
+	assert.Nil(t, err, "Unmarshal should not return an error.")
+	assert.Equal(t, node.X, 1, "Missing key should use the default")
+	assert.Equal(t, node.Next.X, 2, "Section should be read into the pointer")
+	assert.Nil(t, node.Next.Next, "Pointer without a section should not be allocated again")
+
+	node = listNode{}
+	err = Defaults(&node)
+
+	assert.Nil(t, err, "Defaults should not return an error.")
+	assert.Equal(t, node.X, 1, "Zero field should be set")
+	assert.Nil(t, node.Next, "Pointer back to the same struct should be left nil")
+}
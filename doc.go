@@ -7,13 +7,76 @@ The ini syntax supported includes:
 * Comments on new lines starting with # or ;
 * Blank lines
 * Sections labelled with [sectionname]
+* Nested sections labelled with [section.subsection] or [section "subsection"]
 * Split sections, using the same section in more than one place
 * Encoded strings, strings containing \n, \t, etc...
+* Quoted strings, using " or ', the quotes are removed only when a matching pair surrounds the whole value
 * Array values using repeated keys named in the form key[]=value
+* Inline array values in the form key=[a, b, "c,d"], when Options.InlineLists is set
+* Map values using keys named in the form key[name]=value
 * Global key/value pairs that appear before the first section
 
 Repeated keys, that aren't array keys, replace their previous value.
 
+A value with a quote at only one end, or with different quotes at each end, is kept as written, so 'abc" stays 'abc".
+Earlier versions removed every quote from both ends of a value.
+
+Keys are case insensitive. Section names are compared exactly, set Options.CaseInsensitiveSections to make
+[Database] and [database] the same section. Spaces around a section name are ignored, so [ server ] is [server].
+
+Nested sections can be listed with ChildSections and scoped with Sub. Set Options.InheritValues to have
+missing keys in [server.http] fall back to [server] and then to the global values.
+
+Set Options.Extends to let one section inherit the keys of another, using [prod : base] or a key
+named extends=base inside the section.
+
+Booleans use strconv.ParseBool. Set Options.ExtendedBooleans to also accept yes/no, on/off and enabled/disabled,
+or Options.BooleanParser to supply your own vocabulary.
+
+To read a file line by line, including comments and blank lines, use NewScanner and call Next until it returns io.EOF.
+
+Format writes a file in a canonical form, keeping comments with the keys they describe, along with its encoding and line endings. The cmd/minifmt command
+formats files from the command line, with -l and -d flags like gofmt.
+
+ParseDocument reads a file with its comments, Set and Delete edit it and WriteTo writes it back unchanged
+apart from the edits, keeping its byte order mark, UTF-16 encoding and line endings. The cmd/mini command uses it to get, set and delete values from the command line.
+
+ToMap and FromMap convert a config to and from a map[string]interface{}, and the convert package uses them
+to convert configs to and from JSON, YAML and TOML. convert has its own go.mod, so mini itself has no dependencies.
+ToMap returns an error for a global key with the same name as a section, since the map can't hold both.
+
+Unmarshal and Marshal read and write structs the way encoding/json does, with sections for struct fields,
+Unmarshaler and Marshaler for custom types, UnmarshalTypeError for bad values and Decoder.DisallowUnknownFields.
+Decoder.RegisterDecoder adds conversions for other types to a single Decoder, and Decoder.DecodeSection reads a loaded
+section with them, reporting fields it can't read.
+Fields tagged with a default, as in default:"30s", get that value when the key is missing, and Defaults
+sets the defaults without reading a file.
+
+Keys named in Options.SecretKeys, or fields tagged with secret, hold secrets. Their values are shown as Redacted
+by Value.String, ToMap, error messages, Encoder.RedactSecrets and Document.Redact. Options.SecretResolvers
+expands references like file:///run/secrets/db the first time they are read, FileResolver reads them from files.
+
+Values written as ENC[AES256_GCM,...] are decrypted when the file is loaded by Options.Decrypter, such as a KeyFile,
+and are secret. Document.Encrypt, and the encrypt command of cmd/mini, encrypt values in place so a file with
+credentials can be committed and still read and diffed. Call Document.SetOptions with the Options the file is loaded with, so
+[prod : base] is named prod only when Options.Extends is set and [DB] is the section db only with
+Options.CaseInsensitiveSections, as they are when loading.
+
+Origin tells where a value was set: the file and line, which appearance of a split section it was in, the
+section it was inherited from and the earlier assignments it replaced.
+
+By default a repeated key keeps its last value and a repeated section is merged with the first. Options.DuplicateKeys,
+Options.DuplicateSections and Options.MixedKeys, for keys written both as key and key[], can make these an error,
+keep the first value or report a Warning, through Options.Warn or Config.Warnings.
+
+Lint checks a file for likely mistakes, like duplicate keys, values with trailing white space or text that looks like
+a comment, with the rules in DefaultRules and RuleUnknownKeys. OptionalRules holds rules that only guess, like
+RuleUnindentedKey, which aren't run unless they are asked for. The cmd/minilint command runs it from the command line
+and can print SARIF for code scanning tools.
+
+Files are read as UTF-8. A byte order mark is removed, files with a UTF-16 byte order mark are converted to UTF-8,
+and lines can end with \n, \r\n or \r. Options.InvalidUTF8 can reject or replace bytes that aren't valid UTF-8.
+
 copyright © 2015 Fog Creek Software, Inc.
 */
 package mini
@@ -0,0 +1,395 @@
+package mini
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//A line of a document, raw is the line as it was written, without the line ending
+type docLine struct {
+	Token
+	raw    string
+	ending string //"" for lines added to the document, and a last line without an ending
+}
+
+//Return the value as it was written, including any quotes
+func (line *docLine) rawValue() string {
+	return strings.TrimSpace(line.Text[strings.IndexByte(line.Text, '=')+1:])
+}
+
+//A key and the comments and blank lines written before it
+type docEntry struct {
+	comments []docLine
+	line     docLine
+}
+
+//A section, the global section has no header
+type docSection struct {
+	comments []docLine //comments directly above the header
+	header   *docLine
+	entries  []*docEntry
+	trailing []docLine //comments and blank lines after the last entry
+}
+
+//Return the normalized name of the section, used to find split sections. Names are compared the way options load the file
+func (section *docSection) key(options *Options) string {
+	if section.header == nil {
+		return ""
+	}
+	return options.sectionKey(sectionDocKey(section.header.Section, options.Extends))
+}
+
+//Normalize a section name the way the file is loaded, ignoring the section named by [name : base] if extends is set
+func sectionDocKey(name string, extends bool) string {
+	name, _ = splitSectionName(name, extends)
+	return name
+}
+
+/*
+Document is an ini file kept line by line, including comments and blank lines, so that it can be
+formatted or written back without losing anything.
+Comments are attached to the key or section header that follows them.
+*/
+type Document struct {
+	sections []*docSection //the first section holds the global values
+	options  Options       //the options the file is loaded with, see SetOptions
+
+	encoding     textEncoding //the byte order mark and encoding the file was read in
+	newline      string       //the first line ending in the file, used for lines that are added
+	finalNewline bool         //the file ended with a line ending
+}
+
+/*
+ParseDocument reads an ini file into a Document. The document remembers the file's byte order mark or
+UTF-16 encoding and its line endings, and is written back with them.
+*/
+func ParseDocument(input io.Reader) (*Document, error) {
+
+	scanner := NewScanner(input)
+	doc := &Document{sections: []*docSection{{}}, encoding: scanner.encoding, finalNewline: true}
+	current := doc.sections[0]
+
+	var pending []docLine
+
+	for {
+		token, err := scanner.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		line := docLine{Token: token, raw: token.Raw, ending: scanner.ending}
+		doc.finalNewline = len(line.ending) > 0
+
+		if len(doc.newline) == 0 {
+			doc.newline = line.ending
+		}
+
+		switch token.Kind {
+		case Blank, Comment:
+			pending = append(pending, line)
+		case SectionStart:
+			//comments directly above the header belong to it, the rest end the previous section
+			split := len(pending)
+			for split > 0 && pending[split-1].Kind == Comment {
+				split--
+			}
+
+			current.trailing = append(current.trailing, pending[:split]...)
+			current = &docSection{comments: pending[split:], header: &line}
+			doc.sections = append(doc.sections, current)
+			pending = nil
+		default:
+			current.entries = append(current.entries, &docEntry{comments: pending, line: line})
+			pending = nil
+		}
+	}
+
+	current.trailing = append(current.trailing, pending...)
+
+	if len(doc.newline) == 0 {
+		doc.newline = "\n"
+	}
+
+	return doc, nil
+}
+
+/*
+WriteTo writes the document as it was read, in the same encoding and with the same line endings.
+Lines that were added or changed end the way the first line of the file does.
+*/
+func (doc *Document) WriteTo(output io.Writer) (int64, error) {
+
+	var text bytes.Buffer
+	lines := doc.lines()
+
+	for i, line := range lines {
+		text.WriteString(line.raw)
+		ending := line.ending
+
+		if len(ending) == 0 && (i < len(lines)-1 || doc.finalNewline) {
+			ending = doc.newline
+		}
+
+		text.WriteString(ending)
+	}
+
+	n, err := output.Write(doc.encoding.encode(text.Bytes()))
+	return int64(n), err
+}
+
+//Return the lines of the document in order
+func (doc *Document) lines() []*docLine {
+	var lines []*docLine
+
+	add := func(list []docLine) {
+		for i := range list {
+			lines = append(lines, &list[i])
+		}
+	}
+
+	for _, section := range doc.sections {
+		add(section.comments)
+
+		if section.header != nil {
+			lines = append(lines, section.header)
+		}
+
+		for _, entry := range section.entries {
+			add(entry.comments)
+			lines = append(lines, &entry.line)
+		}
+
+		add(section.trailing)
+	}
+
+	return lines
+}
+
+/*
+SetOptions sets the options the file is loaded with, so that sections are named the way the loader names them.
+With Options.Extends set, [prod : base] is the section prod, otherwise it is the section "prod : base".
+With Options.CaseInsensitiveSections set, [DB] is found as the section db.
+*/
+func (doc *Document) SetOptions(options Options) {
+	doc.options = options
+}
+
+//Return every place the section appears, "" is the global section
+func (doc *Document) findSections(sectionName string) []*docSection {
+	key := doc.options.sectionKey(sectionDocKey(sectionName, doc.options.Extends))
+	var sections []*docSection
+
+	for _, section := range doc.sections {
+		if section.key(&doc.options) == key {
+			sections = append(sections, section)
+		}
+	}
+
+	return sections
+}
+
+/*
+Set stores value under key in the section named sectionName, "" is the global section.
+The last assignment to the key is rewritten in place and any other assignments, including array and map
+entries, are removed. A missing key is added after the last key in the section, and a missing section is
+added to the end of the document. The value is quoted if it couldn't be written as is.
+*/
+func (doc *Document) Set(sectionName string, key string, value string) {
+
+	sections := doc.findSections(sectionName)
+
+	if len(sections) == 0 {
+		last := doc.sections[len(doc.sections)-1]
+
+		if len(doc.lines()) > 0 {
+			last.trailing = append(last.trailing, parseDocLine(""))
+		}
+
+		header := parseDocLine("[" + sectionName + "]")
+		sections = []*docSection{{header: &header}}
+		doc.sections = append(doc.sections, sections[0])
+	}
+
+	var target *docEntry
+
+	for _, section := range sections {
+		for _, entry := range section.entries {
+			if entry.line.Kind == KeyValue && strings.EqualFold(entry.line.Key, key) {
+				target = entry
+			}
+		}
+	}
+
+	if target != nil {
+		target.line = parseDocLine(entryLayout(&target.line, target.line.Key, value))
+	} else {
+		section := sections[len(sections)-1]
+		line := parseDocLine(key + "=" + formatText(value))
+
+		if len(section.entries) > 0 {
+			line = parseDocLine(entryLayout(&section.entries[len(section.entries)-1].line, key, value))
+		}
+
+		target = &docEntry{line: line}
+		section.entries = append(section.entries, target)
+	}
+
+	for _, section := range sections {
+		section.removeEntries(key, target)
+	}
+}
+
+/*
+Delete removes every assignment to key in the section named sectionName, including array and map entries,
+along with the comments above them. It returns false if the key wasn't found.
+*/
+func (doc *Document) Delete(sectionName string, key string) bool {
+	deleted := false
+
+	for _, section := range doc.findSections(sectionName) {
+		if section.removeEntries(key, nil) {
+			deleted = true
+		}
+	}
+
+	return deleted
+}
+
+//Remove the entries for key, other than keep, blank lines above a removed entry are kept
+func (section *docSection) removeEntries(key string, keep *docEntry) bool {
+	entries := section.entries[:0]
+	var blanks []docLine
+	removed := false
+
+	for _, entry := range section.entries {
+		if entry != keep && strings.EqualFold(entry.line.Key, key) {
+			for _, line := range entry.comments {
+				if line.Kind == Blank {
+					blanks = append(blanks, line)
+				}
+			}
+
+			removed = true
+			continue
+		}
+
+		if len(blanks) > 0 {
+			entry.comments = append(blanks, entry.comments...)
+			blanks = nil
+		}
+
+		entries = append(entries, entry)
+	}
+
+	section.entries = entries
+	section.trailing = append(blanks, section.trailing...)
+
+	return removed
+}
+
+//Write key and value using the indentation and spacing of an existing line
+func entryLayout(line *docLine, key string, value string) string {
+	indent := line.raw[:len(line.raw)-len(strings.TrimLeft(line.raw, " \t"))]
+	index := strings.IndexByte(line.Text, '=')
+	before := line.Text[:index]
+	after := line.Text[index+1:]
+
+	return indent + key + before[len(strings.TrimRight(before, " \t")):] + "=" +
+		after[:len(after)-len(strings.TrimLeft(after, " \t"))] + formatText(value)
+}
+
+//Read a single line of ini
+func parseDocLine(raw string) docLine {
+	token, _ := NewScanner(strings.NewReader(raw)).Next()
+
+	if len(raw) == 0 {
+		token = Token{Kind: Blank}
+	}
+
+	return docLine{Token: token, raw: raw}
+}
+
+/*
+Redact replaces the values of keys that match secretKeys with Redacted, so the document can be shown or
+diffed without its secrets. The patterns are matched as they are for Options.SecretKeys.
+*/
+func (doc *Document) Redact(secretKeys []string) {
+	options := &Options{SecretKeys: secretKeys}
+
+	for _, section := range doc.sections {
+		for _, entry := range section.entries {
+			line := &entry.line
+
+			if !options.isSecret(section.key(&doc.options), line.Key) {
+				continue
+			}
+
+			entry.line = parseDocLine(entryLayout(line, entryKey(line), Redacted))
+		}
+	}
+}
+
+/*
+Encrypt replaces the values of key in the section named sectionName, including array and map entries, with
+values encrypted by encrypter, leaving the rest of the document as it was. Values that are already encrypted
+are left alone. It returns false if the key wasn't found.
+*/
+func (doc *Document) Encrypt(sectionName string, key string, encrypter Encrypter) (bool, error) {
+	found := false
+
+	for _, section := range doc.findSections(sectionName) {
+		for _, entry := range section.entries {
+			line := &entry.line
+
+			if line.Kind == Comment || !strings.EqualFold(line.Key, key) {
+				continue
+			}
+
+			found = true
+
+			if isEncrypted(line.Value) {
+				continue
+			}
+
+			text, ok := line.Value, true
+
+			if strings.ContainsAny(text, "\\\"\n") {
+				text, ok = unquote(text)
+			}
+
+			path := encryptionPath(&doc.options, sectionDocKey(sectionName, doc.options.Extends), line.Key)
+
+			if !ok {
+				return found, fmt.Errorf("mini: can't encrypt %s, invalid string %q", path, line.Value)
+			}
+
+			enc, err := encrypter.Encrypt(text, path)
+
+			if err != nil {
+				return found, err
+			}
+
+			entry.line = parseDocLine(entryLayout(line, entryKey(line), enc.String()))
+		}
+	}
+
+	return found, nil
+}
+
+//Return the key of a line as it is written, with [] or [name] for array and map entries
+func entryKey(line *docLine) string {
+	switch line.Kind {
+	case ArrayItem:
+		return line.Key + "[]"
+	case MapItem:
+		return line.Key + "[" + line.Name + "]"
+	}
+	return line.Key
+}
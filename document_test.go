@@ -0,0 +1,175 @@
+package mini
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const documentIni = `# global comment
+name = value
+
+
+; about the section
+[Section]
+  key="one"   
+list[]=1
+
+# trailing`
+
+func TestDocumentWriteTo(t *testing.T) {
+
+	doc, err := ParseDocument(strings.NewReader(documentIni))
+
+	assert.Nil(t, err, "Document should parse without error.")
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+
+	assert.Nil(t, err, "Document should write without error.")
+	assert.Equal(t, buf.String(), documentIni, "Document should be written as it was read")
+	assert.Equal(t, n, int64(buf.Len()), "WriteTo should return the number of bytes written")
+}
+
+func TestDocumentComments(t *testing.T) {
+
+	doc, err := ParseDocument(strings.NewReader(documentIni))
+
+	assert.Nil(t, err, "Document should parse without error.")
+	assert.Equal(t, len(doc.sections), 2, "Document should have global values and one section")
+
+	global := doc.sections[0]
+	assert.Equal(t, global.entries[0].comments[0].Text, "# global comment", "Comment should be attached to the key below it")
+	assert.Equal(t, len(global.trailing), 2, "Blank lines before the section comment should end the global section")
+
+	section := doc.sections[1]
+	assert.Equal(t, section.comments[0].Text, "; about the section", "Comment should be attached to the header below it")
+	assert.Equal(t, section.trailing[1].Text, "# trailing", "Comment at the end should trail the section")
+}
+
+func TestDocumentBadLine(t *testing.T) {
+
+	_, err := ParseDocument(strings.NewReader("[section"))
+
+	assert.NotNil(t, err, "Bad section header should be an error.")
+}
+
+func writeDocument(t *testing.T, doc *Document) string {
+	var buf bytes.Buffer
+	_, err := doc.WriteTo(&buf)
+	assert.Nil(t, err, "Document should write without error.")
+	return buf.String()
+}
+
+func TestDocumentSet(t *testing.T) {
+
+	ini := "# keep me\n[db]\n  Host = old\n# port comment\nport=1\n[other]\nx=1\n[db]\nhost=older"
+
+	doc, err := ParseDocument(strings.NewReader(ini))
+	assert.Nil(t, err, "Document should parse without error.")
+
+	doc.Set("db", "host", "new")
+	doc.Set("db", "user", " padded ")
+	doc.Set("", "name", "global")
+	doc.Set("server.http", "port", "80")
+
+	expected := "name=global\n# keep me\n[db]\n# port comment\nport=1\n[other]\nx=1\n[db]\nhost=new\nuser=\" padded \"\n\n[server.http]\nport=80"
+
+	assert.Equal(t, writeDocument(t, doc), expected, "Set should rewrite the last assignment and remove the others")
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(writeDocument(t, doc)))
+	assert.Nil(t, err, "Edited document should load without error.")
+	assert.Equal(t, config.StringFromSection("db", "user", ""), " padded ", "Quoted value should read back")
+	assert.Equal(t, config.IntegerFromSection("server.http", "port", 0), int64(80), "New section should read back")
+}
+
+func TestDocumentCaseInsensitiveSections(t *testing.T) {
+
+	ini := "[DB]\nhost=a\npassword=secret\n[Db]\nport=1\n"
+
+	doc, err := ParseDocument(strings.NewReader(ini))
+	assert.Nil(t, err, "Document should parse without error.")
+
+	doc.Set("db", "host", "b")
+	assert.Equal(t, writeDocument(t, doc), ini+"\n[db]\nhost=b\n", "Sections should be compared exactly by default")
+
+	doc, _ = ParseDocument(strings.NewReader(ini))
+	doc.SetOptions(Options{CaseInsensitiveSections: true})
+
+	doc.Set("db", "host", "b")
+	doc.Set("db", "port", "2")
+	assert.Equal(t, writeDocument(t, doc), "[DB]\nhost=b\npassword=secret\n[Db]\nport=2\n", "Set should edit [DB] and [Db] as the section db")
+
+	found, err := doc.Encrypt("db", "password", testKeyFile(t))
+	assert.True(t, found, "Encrypt should find the key in [DB]")
+	assert.Nil(t, err, "Encrypt should not return an error.")
+	assert.False(t, strings.Contains(writeDocument(t, doc), "secret"), "Value should be encrypted")
+
+	doc.Redact([]string{"db.host"})
+	assert.True(t, strings.HasPrefix(writeDocument(t, doc), "[DB]\nhost="+Redacted+"\n"), "Redact should match [DB] as db")
+}
+
+func TestDocumentSetKeepsLayout(t *testing.T) {
+
+	doc, err := ParseDocument(strings.NewReader("[s]\n\tkey = 1\n\tlist[] = a\n\tlist[] = b"))
+	assert.Nil(t, err, "Document should parse without error.")
+
+	doc.Set("s", "key", "2")
+	doc.Set("s", "new", "3")
+	doc.Set("s", "list", "c")
+
+	assert.Equal(t, writeDocument(t, doc), "[s]\n\tkey = 2\n\tnew = 3\n\tlist = c", "Set should copy the indentation and spacing of the section")
+}
+
+func TestDocumentSetEscapes(t *testing.T) {
+
+	doc, err := ParseDocument(strings.NewReader("[s]\npath=x\n"))
+	assert.Nil(t, err, "Document should parse without error.")
+
+	values := map[string]string{"path": `c:\data`, "say": `say "hi"`, "padded": " padded ", "lines": "a\nb"}
+
+	for key, value := range values {
+		doc.Set("s", key, value)
+	}
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(writeDocument(t, doc)))
+	assert.Nil(t, err, "Written document should load without error.")
+
+	for key, value := range values {
+		assert.Equal(t, config.StringFromSection("s", key, ""), value, "Value should read back unchanged: "+key)
+	}
+}
+
+func TestDocumentKeepsEncoding(t *testing.T) {
+
+	ini := "\ufeff; c\r\na = 1\r\n[s]\r\nb = 2\r\n"
+
+	doc, err := ParseDocument(strings.NewReader(ini))
+	assert.Nil(t, err, "Document should parse without error.")
+	assert.Equal(t, writeDocument(t, doc), ini, "Byte order mark and CRLF should be written back")
+
+	doc.Set("s", "c", "3")
+	doc.Set("t", "d", "4")
+	assert.Equal(t, writeDocument(t, doc), "\ufeff; c\r\na = 1\r\n[s]\r\nb = 2\r\nc = 3\r\n\r\n[t]\r\nd=4\r\n", "Added lines should use CRLF")
+
+	utf16 := []byte{0xff, 0xfe, 'a', 0, '=', 0, 0xe9, 0, '\r', 0, '\n', 0}
+
+	doc, err = ParseDocument(bytes.NewReader(utf16))
+	assert.Nil(t, err, "UTF-16 document should parse without error.")
+	assert.Equal(t, writeDocument(t, doc), string(utf16), "UTF-16 should be written back as UTF-16")
+
+	doc.Set("", "a", "\U0001F600")
+	assert.Equal(t, []byte(writeDocument(t, doc)), []byte{0xff, 0xfe, 'a', 0, '=', 0, 0x3d, 0xd8, 0x00, 0xde, '\r', 0, '\n', 0}, "Characters outside the BMP should be written as surrogates")
+}
+
+func TestDocumentDelete(t *testing.T) {
+
+	doc, err := ParseDocument(strings.NewReader("a=1\n\n# about b\nb=2\nb[]=3\nc=4"))
+	assert.Nil(t, err, "Document should parse without error.")
+
+	assert.True(t, doc.Delete("", "B"), "Delete should find b")
+	assert.False(t, doc.Delete("", "missing"), "Delete should not find missing")
+	assert.False(t, doc.Delete("nosection", "a"), "Delete should not find a missing section")
+	assert.Equal(t, writeDocument(t, doc), "a=1\n\nc=4", "Delete should remove the key and its comments")
+}
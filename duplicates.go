@@ -0,0 +1,152 @@
+package mini
+
+import (
+	"errors"
+	"fmt"
+)
+
+/*
+DuplicatePolicy chooses what happens when a file sets a key twice, starts a section twice or mixes the forms of a key.
+The zero value keeps the last value of a key and merges split sections.
+*/
+type DuplicatePolicy int
+
+const (
+	//DuplicatesAllowed keeps the last value of a key and merges the occurrences of a section
+	DuplicatesAllowed DuplicatePolicy = iota
+	//DuplicatesWarn does the same as DuplicatesAllowed and reports a Warning
+	DuplicatesWarn
+	//DuplicatesError makes loading the file fail
+	DuplicatesError
+	//DuplicatesFirstWins keeps the first value of a key and ignores later occurrences of a section
+	DuplicatesFirstWins
+	//DuplicatesLastWins keeps the last value of a key and replaces the values of a section with those of its last occurrence
+	DuplicatesLastWins
+)
+
+/*
+Warning describes a problem in a file that didn't stop it from loading.
+*/
+type Warning struct {
+	Pos     Position
+	Section string //"" for the global values
+	Key     string //"" for warnings about a section
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Pos.String() + ": " + w.Message
+}
+
+/*
+Warnings returns the warnings from the last time the config was initialized, in the order they were found.
+*/
+func (config *Config) Warnings() []Warning {
+	return config.warnings
+}
+
+//Apply a duplicate policy, returning true if the duplicate should be skipped.
+//The warning is only built by policies that report it, so merging split sections doesn't format messages
+func (config *Config) duplicate(policy DuplicatePolicy, warning func() Warning) (bool, error) {
+	switch policy {
+	case DuplicatesWarn:
+		w := warning()
+		config.warnings = append(config.warnings, w)
+
+		if config.Options.Warn != nil {
+			config.Options.Warn(w)
+		}
+	case DuplicatesError:
+		return false, errors.New("mini: " + warning().String())
+	case DuplicatesFirstWins:
+		return true, nil
+	}
+
+	return false, nil
+}
+
+//Check a key against the value already stored under it, returning true if the line should be skipped
+//The key and name are byte slices so that checking array entries doesn't allocate
+func (config *Config) duplicateKey(section *configSection, sectionName string, key []byte, kind TokenKind, name []byte, pos Position) (bool, error) {
+	existing := section.values[string(key)]
+
+	if existing == nil || (kind == ArrayItem && existing.kind() == ArrayValue) {
+		return false, nil
+	}
+
+	var entry *value
+
+	if kind == MapItem && existing.kind() == MapValue {
+		var ok bool
+
+		if entry, ok = existing.mapEntry(string(name)); !ok {
+			return false, nil
+		}
+	}
+
+	policy := config.Options.DuplicateKeys
+	mixed := !(kind == KeyValue && existing.kind() == ScalarValue) && entry == nil
+
+	if mixed {
+		policy = config.Options.MixedKeys
+	}
+
+	return config.duplicate(policy, func() Warning {
+		keyName := section.keyName(string(key))
+		var message string
+
+		switch {
+		case mixed:
+			message = fmt.Sprintf("key %q is written as %s and as %s, first set at %s", keyName, existing.kind(), kindOfToken(kind), existing.position())
+		case entry != nil:
+			message = fmt.Sprintf("duplicate key %q, first set at %s", keyName+"["+string(name)+"]", entry.position())
+		default:
+			message = fmt.Sprintf("duplicate key %q, first set at %s", keyName, existing.position())
+		}
+
+		return Warning{Pos: pos, Section: sectionName, Key: keyName, Message: message}
+	})
+}
+
+//Return the kind of value a line holds
+func kindOfToken(kind TokenKind) Kind {
+	switch kind {
+	case ArrayItem:
+		return ArrayValue
+	case MapItem:
+		return MapValue
+	}
+	return ScalarValue
+}
+
+//Check a section that is starting again, returning true if its keys should be skipped
+func (config *Config) duplicateSection(section *configSection, pos Position) (bool, error) {
+	if section.occurrences == 0 {
+		section.pos = pos
+		return false, nil
+	}
+
+	warning := func() Warning {
+		return Warning{
+			Pos:     pos,
+			Section: section.name,
+			Message: fmt.Sprintf("duplicate section %q, first started at %s", section.name, section.pos),
+		}
+	}
+
+	if config.Options.DuplicateSections == DuplicatesLastWins {
+		section.clear()
+	}
+
+	return config.duplicate(config.Options.DuplicateSections, warning)
+}
+
+//Remove the values of a section, they are remembered as overridden for Origin
+func (section *configSection) clear() {
+	for key, val := range section.values {
+		section.override(key, val)
+	}
+
+	section.values = make(map[string]*value)
+	section.order = nil
+}
@@ -0,0 +1,104 @@
+package mini
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func loadDuplicates(ini string, options Options) (*Config, error) {
+	config := &Config{Options: options}
+	return config, config.InitializeFromReader(strings.NewReader(ini))
+}
+
+func TestDuplicateKeys(t *testing.T) {
+
+	ini := `first=alpha
+first=beta
+map[a]=1
+map[b]=2
+map[a]=3
+list[]=1
+list[]=2`
+
+	config, err := loadDuplicates(ini, Options{})
+	assert.Nil(t, err, "Duplicates should be allowed by default")
+	assert.Equal(t, config.String("first", ""), "beta", "Last value should win by default")
+	assert.Nil(t, config.Warnings(), "Duplicates should not warn by default")
+
+	config, err = loadDuplicates(ini, Options{DuplicateKeys: DuplicatesFirstWins})
+	assert.Nil(t, err, "Configuration should load without error.")
+	assert.Equal(t, config.String("first", ""), "alpha", "First value should win")
+	assert.Equal(t, config.Map("map")["a"], "1", "First map entry should win")
+	assert.Equal(t, config.Strings("list"), []string{"1", "2"}, "Array entries are not duplicates")
+
+	var warned []Warning
+	config, err = loadDuplicates(ini, Options{DuplicateKeys: DuplicatesWarn, Warn: func(w Warning) { warned = append(warned, w) }})
+	assert.Nil(t, err, "Warnings should not stop loading")
+	assert.Equal(t, config.String("first", ""), "beta", "Last value should win with warnings")
+	assert.Equal(t, len(config.Warnings()), 2, "Duplicate key and map entry should warn")
+	assert.Equal(t, warned, config.Warnings(), "Callback should get the same warnings")
+	assert.Equal(t, config.Warnings()[0].String(), `line 2: duplicate key "first", first set at line 1`, "Warning should have its position")
+	assert.Equal(t, config.Warnings()[1].Key, "map", "Warning should have its key")
+
+	_, err = loadDuplicates(ini, Options{DuplicateKeys: DuplicatesError})
+	assert.NotNil(t, err, "Duplicates should be an error")
+	assert.Equal(t, err.Error(), `mini: line 2: duplicate key "first", first set at line 1`, "Error should have its position")
+}
+
+func TestDuplicateSections(t *testing.T) {
+
+	ini := `[db]
+host=a
+port=1
+[cache]
+[db]
+host=b`
+
+	config, err := loadDuplicates(ini, Options{})
+	assert.Nil(t, err, "Configuration should load without error.")
+	assert.Equal(t, config.StringFromSection("db", "host", ""), "b", "Sections should be merged by default")
+	assert.Equal(t, config.IntegerFromSection("db", "port", 0), int64(1), "Sections should be merged by default")
+
+	config, err = loadDuplicates(ini, Options{DuplicateSections: DuplicatesFirstWins})
+	assert.Nil(t, err, "Configuration should load without error.")
+	assert.Equal(t, config.StringFromSection("db", "host", ""), "a", "Later occurrences should be ignored")
+
+	config, err = loadDuplicates(ini, Options{DuplicateSections: DuplicatesLastWins})
+	assert.Nil(t, err, "Configuration should load without error.")
+	assert.Equal(t, config.StringFromSection("db", "host", ""), "b", "Last occurrence should win")
+	assert.Equal(t, config.IntegerFromSection("db", "port", 0), int64(0), "Earlier occurrences should be replaced")
+
+	origin, _ := config.Origin("db", "host")
+	assert.Equal(t, len(origin.Overridden), 1, "Replaced values should be overridden")
+
+	config, err = loadDuplicates(ini, Options{DuplicateSections: DuplicatesWarn})
+	assert.Nil(t, err, "Warnings should not stop loading")
+	assert.Equal(t, config.Warnings()[0].String(), `line 5: duplicate section "db", first started at line 1`, "Warning should have its position")
+
+	_, err = loadDuplicates(ini, Options{DuplicateSections: DuplicatesError})
+	assert.NotNil(t, err, "Duplicate sections should be an error")
+}
+
+func TestMixedKeys(t *testing.T) {
+
+	ini := `[section]
+key=nope
+key[]=one
+key[]=two`
+
+	config, err := loadDuplicates(ini, Options{DuplicateKeys: DuplicatesError})
+	assert.Nil(t, err, "Mixed keys should use their own policy")
+	assert.Equal(t, config.StringsFromSection("section", "key"), []string{"one", "two"}, "Last form should win by default")
+
+	config, err = loadDuplicates(ini, Options{MixedKeys: DuplicatesFirstWins})
+	assert.Nil(t, err, "Configuration should load without error.")
+	assert.Equal(t, config.StringFromSection("section", "key", ""), "nope", "First form should win")
+
+	config, _ = loadDuplicates(ini, Options{MixedKeys: DuplicatesWarn})
+	assert.Equal(t, len(config.Warnings()), 1, "Only the change of form should warn")
+	assert.Equal(t, config.Warnings()[0].Message, `key "key" is written as scalar and as array, first set at line 2`, "Warning should name the forms")
+
+	_, err = loadDuplicates(ini, Options{MixedKeys: DuplicatesError})
+	assert.NotNil(t, err, "Mixed keys should be an error")
+}
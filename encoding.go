@@ -0,0 +1,961 @@
+package mini
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+Unmarshaler is implemented by types that can read themselves from the text of a value.
+The text has its quotes removed and its escapes decoded.
+*/
+type Unmarshaler interface {
+	UnmarshalINI(text []byte) error
+}
+
+/*
+Marshaler is implemented by types that can write themselves as the text of a value.
+The text is quoted and escaped as needed when it's written.
+*/
+type Marshaler interface {
+	MarshalINI() ([]byte, error)
+}
+
+var (
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	marshalerType       = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+/*
+ErrUnsupportedType is returned, inside an UnmarshalTypeError, for a value whose field has a type that Decode
+can't read. Decoder.RegisterDecoder adds support for more types.
+*/
+var ErrUnsupportedType = errors.New("mini: unsupported type")
+
+/*
+UnmarshalTypeError describes a value that couldn't be stored in a field of the type given to Unmarshal or Decode.
+*/
+type UnmarshalTypeError struct {
+	Value   string       //the text of the value, as it was written
+	Type    reflect.Type //the type of the field
+	Section string       //the section of the value, "" for the global values
+	Key     string
+	Pos     Position
+	Err     error //the error from the conversion, if there was one
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	where := strconv.Quote(e.Key)
+
+	if len(e.Section) > 0 {
+		where += " in section " + strconv.Quote(e.Section)
+	}
+
+	msg := "mini: cannot unmarshal " + strconv.Quote(e.Value) + " into key " + where + " of type " + e.Type.String()
+
+	if e.Pos.Line > 0 {
+		msg += " at " + e.Pos.String()
+	}
+
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+func (e *UnmarshalTypeError) Unwrap() error {
+	return e.Err
+}
+
+//A struct field that holds a value or a section, read from its ini tag
+type structField struct {
+	index     []int
+	name      string
+	omitEmpty bool
+	sep       string
+	hasSep    bool
+	def       string //the default from the field's default tag
+	hasDef    bool
+	secret    bool //tagged with secret, the value is redacted in errors and by Encoder.RedactSecrets
+}
+
+/*
+Return the fields of a struct type, the fields of embedded structs are included as if they were in the struct.
+Fields are named by their ini tag, as in `ini:"name,omitempty"`, or by the field name. A tag of "-" skips the field.
+The secret option, as in `ini:"password,secret"`, marks a field that holds a secret.
+*/
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("ini")
+
+		if tag == "-" {
+			continue
+		}
+
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct && len(tag) == 0 {
+			for _, embedded := range structFields(sf.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				fields = append(fields, embedded)
+			}
+			continue
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		field := structField{index: []int{i}, name: sf.Name}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if len(name) > 0 {
+			field.name = name
+		}
+
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty":
+				field.omitEmpty = true
+			case "secret":
+				field.secret = true
+			}
+		}
+
+		field.sep, field.hasSep = sf.Tag.Lookup("sep")
+		field.def, field.hasDef = sf.Tag.Lookup("default")
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+//Return true if values of type t are stored as sections rather than single values
+func isSectionType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	ptr := reflect.PtrTo(t)
+
+	return !ptr.Implements(unmarshalerType) && !ptr.Implements(textUnmarshalerType) &&
+		!ptr.Implements(marshalerType) && !ptr.Implements(textMarshalerType)
+}
+
+/*
+A Decoder reads an ini file into a struct.
+*/
+type Decoder struct {
+	input                 io.Reader
+	options               Options
+	disallowUnknownFields bool
+	decoders              map[reflect.Type]func(text string) (interface{}, error)
+}
+
+/*
+NewDecoder returns a Decoder that reads from input.
+*/
+func NewDecoder(input io.Reader) *Decoder {
+	return &Decoder{input: input}
+}
+
+/*
+DisallowUnknownFields makes Decode return an error for keys and sections that don't match a field.
+*/
+func (dec *Decoder) DisallowUnknownFields() {
+	dec.disallowUnknownFields = true
+}
+
+/*
+SetOptions sets the options used to read the file.
+*/
+func (dec *Decoder) SetOptions(options Options) {
+	dec.options = options
+}
+
+/*
+RegisterDecoder makes the decoder use decode to read fields of type t. Decode is passed the text of the value,
+with its quotes removed and its escapes decoded, and returns a value that can be assigned or converted to t.
+Errors from decode are returned inside an UnmarshalTypeError.
+
+Decoders are checked before anything else, so they can be used for types that would otherwise be read
+as sections, like *regexp.Regexp, or to replace the conversion of a basic type. A decoder registered for T
+is also used for fields of type *T.
+*/
+func (dec *Decoder) RegisterDecoder(t reflect.Type, decode func(text string) (interface{}, error)) {
+	if dec.decoders == nil {
+		dec.decoders = make(map[reflect.Type]func(string) (interface{}, error))
+	}
+	dec.decoders[t] = decode
+}
+
+/*
+Unmarshal reads an ini file into the struct pointed to by v, see Decoder.Decode.
+*/
+func Unmarshal(data []byte, v interface{}) error {
+	return NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+/*
+Decode reads the ini file into the struct pointed to by v.
+
+Fields are matched to keys without regard to case, using the field name or the name in an ini tag,
+as in `ini:"name"`. Fields that are structs are read from the section with the field's name, and struct
+fields inside them from nested sections, so a field Http inside a field Server reads [server.http].
+Fields of embedded structs are read as if they were fields of the outer struct.
+
+Values can be read into strings, booleans, every size of int, uint and float, time.Duration, pointers to these,
+and types that implement Unmarshaler or encoding.TextUnmarshaler. Slices are read from array values, or from a
+single value split with a sep tag as in DataFromSection, and maps with string keys are read from map values.
+
+Missing keys leave their fields unchanged, unless the field has a default tag, as in `default:"30s"`.
+The default is read the way a value in the file would be, except that inline lists are always read, so `default:"[a, b]"` fills a slice.
+Structs for missing sections are filled with their defaults. If a value can't be stored in its field, Decode carries on and returns
+an UnmarshalTypeError for the first such value.
+*/
+func (dec *Decoder) Decode(v interface{}) error {
+
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("mini: Decode needs a non-nil pointer to a struct, not %T", v)
+	}
+
+	config := &Config{Options: dec.options}
+
+	if err := config.InitializeFromReader(dec.input); err != nil {
+		return err
+	}
+
+	state := &decodeState{dec: dec, config: config, decoded: make(map[*configSection]bool)}
+
+	if err := state.decodeSection(&(config.configSection), "", rv.Elem()); err != nil {
+		return err
+	}
+
+	if dec.disallowUnknownFields {
+		for _, section := range config.sectionOrder {
+			if !state.decoded[section] {
+				return fmt.Errorf("mini: unknown section %q", section.name)
+			}
+		}
+	}
+
+	return state.err
+}
+
+/*
+DecodeSection reads a section of a config that has already been loaded into the struct pointed to by v,
+using the same rules, decoders and DisallowUnknownFields setting as Decode. Unlike DataFromSection it returns
+an error for values it can't read, including those whose fields have an unsupported type.
+The decoder's reader and options aren't used, so the decoder can be created with NewDecoder(nil).
+
+If the section name matches the config.name or "" the global data is read.
+*/
+func (dec *Decoder) DecodeSection(config *Config, sectionName string, v interface{}) error {
+
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("mini: DecodeSection needs a non-nil pointer to a struct, not %T", v)
+	}
+
+	section := config.sectionForName(sectionName)
+
+	if section == nil {
+		return &ValueError{Section: sectionName, Err: ErrNotFound}
+	}
+
+	if section == &(config.configSection) {
+		sectionName = ""
+	}
+
+	state := &decodeState{dec: dec, config: config, decoded: make(map[*configSection]bool)}
+
+	if err := state.decodeSection(section, sectionName, rv.Elem()); err != nil {
+		return err
+	}
+
+	return state.err
+}
+
+//The state of a Decode call
+type decodeState struct {
+	dec     *Decoder
+	config  *Config
+	decoded map[*configSection]bool //the sections that matched a struct
+	filling map[reflect.Type]int    //the struct types being filled, counting each level
+	err     error                   //the first UnmarshalTypeError
+}
+
+//Mark the struct type t as being filled until the returned func is called
+func (state *decodeState) enter(t reflect.Type) func() {
+	if state.filling == nil {
+		state.filling = make(map[reflect.Type]int)
+	}
+
+	state.filling[t]++
+	return func() { state.filling[t]-- }
+}
+
+/*
+Check if a section field is a nil pointer to a struct that is already being filled, like the Next field of a list node.
+Without a section of its own it would be allocated and filled forever, so it is left nil.
+*/
+func (state *decodeState) cycles(fv reflect.Value) bool {
+	return fv.Kind() == reflect.Ptr && fv.IsNil() && state.filling[fv.Type().Elem()] > 0
+}
+
+//Find the section for a struct field, ignoring case like keys do even when the config compares section names exactly
+func (state *decodeState) fieldSection(name string) *configSection {
+	if section := state.config.sectionForName(name); section != nil {
+		return section
+	}
+
+	for _, section := range state.config.sectionOrder {
+		if strings.EqualFold(section.name, name) {
+			return section
+		}
+	}
+	return nil
+}
+
+//Store the keys of section in the fields of the struct v
+func (state *decodeState) decodeSection(section *configSection, sectionName string, v reflect.Value) error {
+
+	state.decoded[section] = true
+	defer state.enter(v.Type())()
+	known := make(map[string]bool)
+
+	for _, field := range structFields(v.Type()) {
+		fv := v.FieldByIndex(field.index)
+
+		if isSectionType(fv.Type()) && !state.registered(fv.Type()) {
+			childName := field.name
+
+			if len(sectionName) > 0 {
+				childName = sectionName + "." + field.name
+			}
+
+			child := state.fieldSection(childName)
+
+			if child == nil && (!hasDefaults(fv.Type(), nil) || state.cycles(fv)) {
+				continue
+			}
+
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+				fv = fv.Elem()
+			}
+
+			if err := state.decodeSection(child, childName, fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		known[strings.ToLower(field.name)] = true
+		val, ok := section.lookup(field.name)
+
+		if !ok && !field.hasDef {
+			continue
+		}
+
+		if !ok {
+			val = defaultValue(field.def, &state.config.Options)
+		}
+
+		if err := state.keepTypeError(state.decodeValue(val, field, fv, sectionName)); err != nil {
+			return err
+		}
+	}
+
+	if state.dec.disallowUnknownFields && section != nil {
+		for _, key := range section.order {
+			if !known[strings.ToLower(key)] {
+				if len(sectionName) == 0 {
+					return fmt.Errorf("mini: unknown key %q", key)
+				}
+				return fmt.Errorf("mini: unknown key %q in section %q", key, sectionName)
+			}
+		}
+	}
+
+	return nil
+}
+
+//Remember the first UnmarshalTypeError so decoding can carry on, other errors are returned
+func (state *decodeState) keepTypeError(err error) error {
+	var typeErr *UnmarshalTypeError
+
+	if err == nil || !errors.As(err, &typeErr) {
+		return err
+	}
+
+	if state.err == nil {
+		state.err = err
+	}
+
+	return nil
+}
+
+//Store a value in the field fv
+func (state *decodeState) decodeValue(val *value, field structField, fv reflect.Value, sectionName string) error {
+
+	val = val.resolve()
+
+	if err := val.failed(); err != nil {
+		return err
+	}
+
+	typeError := func(raw string, err error) error {
+		if field.secret || val.isSecret() {
+			raw, err = Redacted, redactSecret(err, raw)
+		}
+
+		return &UnmarshalTypeError{Value: raw, Type: fv.Type(), Section: sectionName, Key: field.name, Pos: val.position(), Err: err}
+	}
+
+	t := fv.Type()
+
+	if isTextType(t) || state.registered(t) {
+		return state.decodeScalar(val, fv, typeError)
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		entries := val.list()
+
+		if field.hasSep && val.kind() == ScalarValue && val.entries() == nil {
+			list := splitList(val.raw, field.sep)
+			entries = make([]*value, len(list))
+
+			for i, entry := range list {
+				entries[i] = newValue(entry, int(val.line), val.src)
+			}
+		}
+
+		if entries == nil {
+			return typeError(val.raw, val.kindError())
+		}
+
+		if t.Kind() == reflect.Slice {
+			fv.Set(reflect.MakeSlice(t, len(entries), len(entries)))
+		}
+
+		for i := 0; i < len(entries) && i < fv.Len(); i++ {
+			if err := state.decodeScalar(entries[i], fv.Index(i), typeError); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case reflect.Map:
+		if val.kind() != MapValue || t.Key().Kind() != reflect.String {
+			return typeError(val.raw, errors.New("not a map value with string keys"))
+		}
+
+		m := reflect.MakeMapWithSize(t, len(val.mapEntries()))
+
+		for i, entry := range val.mapEntries() {
+			elem := reflect.New(t.Elem()).Elem()
+
+			if err := state.decodeScalar(entry, elem, typeError); err != nil {
+				return err
+			}
+
+			m.SetMapIndex(reflect.ValueOf(val.more.names[i]).Convert(t.Key()), elem)
+		}
+
+		fv.Set(m)
+		return nil
+	}
+
+	return state.decodeScalar(val, fv, typeError)
+}
+
+//Store a single value in fv
+func (state *decodeState) decodeScalar(val *value, fv reflect.Value, typeError func(string, error) error) error {
+
+	val = val.resolve()
+
+	if err := val.failed(); err != nil {
+		return err
+	}
+
+	if val.kind() != ScalarValue {
+		return typeError(val.raw, val.kindError())
+	}
+
+	c := val.converted()
+
+	if !c.textOK {
+		_, err := Value{val}.Text()
+		return typeError(val.raw, err)
+	}
+
+	if err := state.decodeText(c.text, fv); err != nil {
+		if methodErr, ok := err.(*methodError); ok {
+			return methodErr.err
+		}
+
+		return typeError(val.raw, err)
+	}
+
+	return nil
+}
+
+//An error returned by an Unmarshaler or encoding.TextUnmarshaler, these are returned as they are
+type methodError struct {
+	err error
+}
+
+func (e *methodError) Error() string {
+	return e.err.Error()
+}
+
+//Return true if a decoder is registered for t, or the type t points to
+func (state *decodeState) registered(t reflect.Type) bool {
+	if _, ok := state.dec.decoders[t]; ok {
+		return true
+	}
+
+	if t.Kind() == reflect.Ptr {
+		_, ok := state.dec.decoders[t.Elem()]
+		return ok
+	}
+
+	return false
+}
+
+//Store the result of a registered decoder in fv
+func setDecoded(fv reflect.Value, result interface{}, err error) error {
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(result)
+	t := fv.Type()
+
+	switch {
+	case !rv.IsValid():
+		fv.Set(reflect.Zero(t))
+	case rv.Type().AssignableTo(t):
+		fv.Set(rv)
+	case rv.Type().ConvertibleTo(t):
+		fv.Set(rv.Convert(t))
+	default:
+		return fmt.Errorf("mini: decoder for %s returned a %s", t, rv.Type())
+	}
+
+	return nil
+}
+
+//Convert text to the type of fv and store it
+func (state *decodeState) decodeText(text string, fv reflect.Value) error {
+
+	if decode, ok := state.dec.decoders[fv.Type()]; ok {
+		result, err := decode(text)
+		return setDecoded(fv, result, err)
+	}
+
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return state.decodeText(text, fv.Elem())
+	}
+
+	if handled, err := unmarshalText(text, fv); handled {
+		return err
+	}
+
+	t := fv.Type()
+
+	if t == durationType {
+		d, err := time.ParseDuration(text)
+
+		if err != nil {
+			return err
+		}
+
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		fv.SetString(text)
+	case reflect.Bool:
+		b, err := state.config.Options.parseBoolean(text)
+
+		if err != nil {
+			return err
+		}
+
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(text, 0, t.Bits())
+
+		if err != nil {
+			return err
+		}
+
+		fv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(text, 0, t.Bits())
+
+		if err != nil {
+			return err
+		}
+
+		fv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, t.Bits())
+
+		if err != nil {
+			return err
+		}
+
+		fv.SetFloat(f)
+	case reflect.Slice:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return ErrUnsupportedType
+		}
+
+		fv.SetBytes([]byte(text))
+	case reflect.Interface:
+		if fv.NumMethod() != 0 {
+			return ErrUnsupportedType
+		}
+
+		fv.Set(reflect.ValueOf(text))
+	default:
+		return ErrUnsupportedType
+	}
+
+	return nil
+}
+
+//Read text with the Unmarshaler or encoding.TextUnmarshaler methods of fv, if it has them
+func unmarshalText(text string, fv reflect.Value) (bool, error) {
+	if !fv.CanAddr() {
+		return false, nil
+	}
+
+	var err error
+
+	switch u := fv.Addr().Interface().(type) {
+	case Unmarshaler:
+		err = u.UnmarshalINI([]byte(text))
+	case encoding.TextUnmarshaler:
+		err = u.UnmarshalText([]byte(text))
+	default:
+		return false, nil
+	}
+
+	if err != nil {
+		return true, &methodError{err}
+	}
+
+	return true, nil
+}
+
+//Return true if values of type t are read from a single value, even though they are slices or structs
+func isTextType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	ptr := reflect.PtrTo(t)
+
+	if ptr.Implements(unmarshalerType) || ptr.Implements(textUnmarshalerType) {
+		return true
+	}
+
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+/*
+An Encoder writes structs as ini files.
+*/
+type Encoder struct {
+	output io.Writer
+	redact bool
+}
+
+/*
+NewEncoder returns an Encoder that writes to output.
+*/
+func NewEncoder(output io.Writer) *Encoder {
+	return &Encoder{output: output}
+}
+
+/*
+RedactSecrets makes the encoder write Redacted in place of the values of fields tagged with secret,
+so the output can be logged or shown.
+*/
+func (enc *Encoder) RedactSecrets() {
+	enc.redact = true
+}
+
+/*
+Marshal returns the ini encoding of the struct v, see Encoder.Encode.
+*/
+func Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+/*
+Encode writes the struct v, or the struct it points to, as an ini file.
+
+Fields are named as they are for Decode. Fields that are structs are written as sections, after the values,
+and slices and maps are written as key[]=value and key[name]=value. Nil pointers, nil slices and nil maps
+are left out, as are zero values of fields tagged with omitempty. Text that would read differently
+without quotes is quoted and escaped.
+*/
+func (enc *Encoder) Encode(v interface{}) error {
+
+	rv := reflect.ValueOf(v)
+
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("mini: Encode needs a struct, not %T", v)
+	}
+
+	//make the struct addressable, so methods with pointer receivers can be used
+	addressable := reflect.New(rv.Type()).Elem()
+	addressable.Set(rv)
+
+	writer := bufio.NewWriter(enc.output)
+	state := &encodeState{writer: writer, redact: enc.redact}
+
+	if err := state.encodeSection("", addressable); err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
+
+//The state of an Encode call
+type encodeState struct {
+	writer *bufio.Writer
+	wrote  bool //a line has been written
+	redact bool //write Redacted for secret fields
+}
+
+func (state *encodeState) encodeSection(sectionName string, v reflect.Value) error {
+
+	var sections []structField
+
+	if len(sectionName) > 0 {
+		if state.wrote {
+			state.writer.WriteByte('\n')
+		}
+
+		state.writer.WriteString("[" + sectionName + "]\n")
+		state.wrote = true
+	}
+
+	for _, field := range structFields(v.Type()) {
+		fv := v.FieldByIndex(field.index)
+
+		if isSectionType(fv.Type()) {
+			sections = append(sections, field)
+			continue
+		}
+
+		if field.omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		if field.secret && state.redact {
+			state.writer.WriteString(field.name + "=" + Redacted + "\n")
+			state.wrote = true
+			continue
+		}
+
+		if err := state.encodeValue(field.name, fv, sectionName); err != nil {
+			return err
+		}
+	}
+
+	for _, field := range sections {
+		fv := v.FieldByIndex(field.index)
+
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		childName := field.name
+
+		if len(sectionName) > 0 {
+			childName = sectionName + "." + field.name
+		}
+
+		if err := state.encodeSection(childName, fv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (state *encodeState) encodeValue(key string, fv reflect.Value, sectionName string) error {
+
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+
+	t := fv.Type()
+
+	if !isTextType(t) && !implementsMarshaler(t) {
+		switch t.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < fv.Len(); i++ {
+				if err := state.encodeLine(key+"[]", fv.Index(i), sectionName, key); err != nil {
+					return err
+				}
+			}
+			return nil
+		case reflect.Map:
+			names := fv.MapKeys()
+
+			sort.Slice(names, func(i, j int) bool {
+				return names[i].String() < names[j].String()
+			})
+
+			for _, name := range names {
+				if name.Kind() != reflect.String {
+					return fmt.Errorf("mini: can't marshal key %q of type %s, map keys must be strings", key, t)
+				}
+
+				if err := state.encodeLine(key+"["+name.String()+"]", fv.MapIndex(name), sectionName, key); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	return state.encodeLine(key, fv, sectionName, key)
+}
+
+func (state *encodeState) encodeLine(name string, fv reflect.Value, sectionName string, key string) error {
+
+	text, err := encodeText(fv)
+
+	if errors.Is(err, ErrUnsupportedType) {
+		if len(sectionName) > 0 {
+			return fmt.Errorf("mini: can't marshal key %q in section %q of type %s", key, sectionName, fv.Type())
+		}
+		return fmt.Errorf("mini: can't marshal key %q of type %s", key, fv.Type())
+	}
+
+	if err != nil {
+		return err
+	}
+
+	state.writer.WriteString(name + "=" + formatText(text) + "\n")
+	state.wrote = true
+	return nil
+}
+
+func implementsMarshaler(t reflect.Type) bool {
+	ptr := reflect.PtrTo(t)
+	return ptr.Implements(marshalerType) || ptr.Implements(textMarshalerType)
+}
+
+//Return a single value as text
+func encodeText(fv reflect.Value) (string, error) {
+
+	for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return "", nil
+		}
+		fv = fv.Elem()
+	}
+
+	if !fv.CanAddr() {
+		copied := reflect.New(fv.Type()).Elem()
+		copied.Set(fv)
+		fv = copied
+	}
+
+	switch m := fv.Addr().Interface().(type) {
+	case Marshaler:
+		text, err := m.MarshalINI()
+		return string(text), err
+	case encoding.TextMarshaler:
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+
+	if fv.Type() == durationType {
+		return time.Duration(fv.Int()).String(), nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		return fv.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(fv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(fv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(fv.Float(), 'g', -1, fv.Type().Bits()), nil
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() == reflect.Uint8 {
+			return string(fv.Bytes()), nil
+		}
+	}
+
+	return "", ErrUnsupportedType
+}
+
+//Write text so that it reads back unchanged, escaping it if needed and quoting it if it would be trimmed
+func formatText(text string) string {
+	escaped := escapeText(text)
+
+	if strings.TrimSpace(escaped) != escaped || trimQuotes(escaped) != escaped {
+		return "\"" + escaped + "\""
+	}
+
+	return escaped
+}
@@ -0,0 +1,322 @@
+package mini
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+type level int
+
+func (l *level) UnmarshalINI(text []byte) error {
+	switch string(text) {
+	case "debug":
+		*l = 1
+	case "info":
+		*l = 2
+	default:
+		return errors.New("unknown level " + string(text))
+	}
+	return nil
+}
+
+func (l level) MarshalINI() ([]byte, error) {
+	if l == 1 {
+		return []byte("debug"), nil
+	}
+	return []byte("info"), nil
+}
+
+type httpSettings struct {
+	Port    uint16
+	Headers map[string]string
+}
+
+type serverSettings struct {
+	Host    string
+	Timeout time.Duration
+	Addr    netip.Addr
+	Http    *httpSettings
+}
+
+type common struct {
+	Name string
+}
+
+type appSettings struct {
+	common
+	Level   level
+	Ratio   float32
+	Debug   *bool
+	Hosts   []string
+	Ports   []int `ini:"port_list" sep:","`
+	Skipped string `ini:"-"`
+	Server  serverSettings
+}
+
+const encodingIni = `name=app
+level=debug
+ratio=0.5
+debug=true
+hosts[]=a
+hosts[]=b
+port_list=80, 443
+
+[server]
+host=example.com
+timeout=5s
+addr=10.0.0.1
+
+[server.http]
+port=8080
+headers[Accept]=text/html`
+
+func TestUnmarshal(t *testing.T) {
+
+	var settings appSettings
+	err := Unmarshal([]byte(encodingIni), &settings)
+
+	assert.Nil(t, err, "Unmarshal should not return an error.")
+	assert.Equal(t, settings.Name, "app", "Embedded field should be read")
+	assert.Equal(t, settings.Level, level(1), "Unmarshaler should be used")
+	assert.Equal(t, settings.Ratio, float32(0.5), "Read ratio wrong")
+	assert.Equal(t, *settings.Debug, true, "Pointer should be allocated")
+	assert.Equal(t, settings.Hosts, []string{"a", "b"}, "Read hosts wrong")
+	assert.Equal(t, settings.Ports, []int{80, 443}, "Tagged field with sep should be split")
+	assert.Equal(t, settings.Server.Host, "example.com", "Struct field should be read from a section")
+	assert.Equal(t, settings.Server.Timeout, 5*time.Second, "Read timeout wrong")
+	assert.Equal(t, settings.Server.Addr, netip.MustParseAddr("10.0.0.1"), "TextUnmarshaler should be used")
+	assert.Equal(t, settings.Server.Http.Port, uint16(8080), "Nested struct should be read from a nested section")
+	assert.Equal(t, settings.Server.Http.Headers, map[string]string{"Accept": "text/html"}, "Read headers wrong")
+}
+
+func TestUnmarshalTypeError(t *testing.T) {
+
+	var settings struct {
+		A int8
+		B int
+		C string
+		D []int
+	}
+
+	err := Unmarshal([]byte("a=300\nb=2\nc=[x]\nd[x]=1"), &settings)
+
+	var typeErr *UnmarshalTypeError
+	assert.True(t, errors.As(err, &typeErr), "Out of range value should be an UnmarshalTypeError")
+	assert.Equal(t, typeErr.Key, "A", "Error should be for the first bad key")
+	assert.Equal(t, typeErr.Value, "300", "Error should hold the value")
+	assert.Equal(t, typeErr.Pos.Line, 1, "Error should hold the line")
+	assert.Equal(t, settings.B, 2, "Decode should carry on after a type error")
+	assert.Equal(t, settings.C, "[x]", "Inline array text should be read into a string")
+	assert.Nil(t, settings.D, "Map value should not be read into a slice")
+}
+
+func TestUnmarshalerError(t *testing.T) {
+
+	var settings appSettings
+	err := Unmarshal([]byte("level=loud"), &settings)
+
+	assert.NotNil(t, err, "Unmarshaler error should be returned.")
+	assert.Equal(t, err.Error(), "unknown level loud", "Unmarshaler error should be returned as is")
+}
+
+func TestDecoderDisallowUnknownFields(t *testing.T) {
+
+	var settings struct {
+		Name   string
+		Server struct{ Host string }
+	}
+
+	for _, ini := range []string{"name=a\nextra=1", "[server]\nport=1", "[other]\nx=1"} {
+		decoder := NewDecoder(strings.NewReader(ini))
+		decoder.DisallowUnknownFields()
+
+		assert.NotNil(t, decoder.Decode(&settings), "Unknown key or section should be an error: "+ini)
+		assert.Nil(t, NewDecoder(strings.NewReader(ini)).Decode(&settings), "Unknown fields should be ignored by default: "+ini)
+	}
+}
+
+func TestDecoderOptions(t *testing.T) {
+
+	var settings struct{ Debug bool }
+
+	decoder := NewDecoder(strings.NewReader("debug=yes"))
+	decoder.SetOptions(Options{ExtendedBooleans: true})
+
+	assert.Nil(t, decoder.Decode(&settings), "Decode should not return an error.")
+	assert.True(t, settings.Debug, "Options should be used to read booleans")
+}
+
+func TestDecodeNeedsPointer(t *testing.T) {
+
+	var settings appSettings
+
+	assert.NotNil(t, Unmarshal([]byte("a=1"), settings), "Decode should need a pointer")
+	assert.NotNil(t, Unmarshal([]byte("a=1"), new(int)), "Decode should need a struct")
+}
+
+func TestMarshal(t *testing.T) {
+
+	debug := false
+	settings := appSettings{
+		common: common{Name: `c:\data`},
+		Level:  1,
+		Debug:  &debug,
+		Hosts:  []string{" a ", "b"},
+		Server: serverSettings{
+			Timeout: time.Minute,
+			Addr:    netip.MustParseAddr("::1"),
+			Http:    &httpSettings{Port: 80, Headers: map[string]string{"B": "2", "A": "1"}},
+		},
+	}
+
+	data, err := Marshal(&settings)
+
+	assert.Nil(t, err, "Marshal should not return an error.")
+	assert.Equal(t, string(data), `Name=c:\\data
+Level=debug
+Ratio=0
+Debug=false
+Hosts[]=" a "
+Hosts[]=b
+
+[Server]
+Host=
+Timeout=1m0s
+Addr=::1
+
+[Server.Http]
+Port=80
+Headers[A]=1
+Headers[B]=2
+`, "Marshal wrote the wrong ini")
+
+	var decoded appSettings
+	err = Unmarshal(data, &decoded)
+
+	assert.Nil(t, err, "Marshalled data should unmarshal without error.")
+	assert.Equal(t, decoded, settings, "Round trip should keep every field")
+}
+
+func TestMarshalOmitEmpty(t *testing.T) {
+
+	settings := struct {
+		A string `ini:"a,omitempty"`
+		B int    `ini:"b,omitempty"`
+		C *int
+		D []string
+	}{B: 1}
+
+	data, err := Marshal(settings)
+
+	assert.Nil(t, err, "Marshal should not return an error.")
+	assert.Equal(t, string(data), "b=1\n", "Empty fields should be left out")
+}
+
+func TestMarshalUnsupported(t *testing.T) {
+
+	_, err := Marshal(struct{ C chan int }{})
+	assert.NotNil(t, err, "Channels can't be marshalled")
+
+	_, err = Marshal(3)
+	assert.NotNil(t, err, "Only structs can be marshalled")
+}
+
+type color int
+
+func TestRegisterDecoder(t *testing.T) {
+
+	var settings struct {
+		Pattern *regexp.Regexp
+		Colors  []color
+		Hue     *color
+		Name    string
+	}
+
+	decoder := NewDecoder(strings.NewReader("pattern=^a+$\ncolors=[red, blue]\nhue=blue\nname=x"))
+	decoder.SetOptions(Options{InlineLists: true})
+	decoder.RegisterDecoder(reflect.TypeOf((*regexp.Regexp)(nil)), func(text string) (interface{}, error) {
+		return regexp.Compile(text)
+	})
+	decoder.RegisterDecoder(reflect.TypeOf(color(0)), func(text string) (interface{}, error) {
+		switch text {
+		case "red":
+			return 1, nil
+		case "blue":
+			return 2, nil
+		}
+		return nil, errors.New("unknown color")
+	})
+	decoder.RegisterDecoder(reflect.TypeOf(""), func(text string) (interface{}, error) {
+		return strings.ToUpper(text), nil
+	})
+
+	err := decoder.Decode(&settings)
+
+	assert.Nil(t, err, "Decode should not return an error.")
+	assert.True(t, settings.Pattern.MatchString("aaa"), "Registered decoder should be used for a pointer to a struct")
+	assert.Equal(t, settings.Colors, []color{1, 2}, "Registered decoder should be used for array entries")
+	assert.Equal(t, *settings.Hue, color(2), "Decoder for T should be used for *T")
+	assert.Equal(t, settings.Name, "X", "Registered decoder should replace a basic conversion")
+
+	err = decoder.DecodeSection(&Config{}, "", &settings)
+	assert.Nil(t, err, "Empty config should decode without error.")
+
+	other := NewDecoder(strings.NewReader("hue=blue"))
+	err = other.Decode(&settings)
+
+	var typeErr *UnmarshalTypeError
+	assert.True(t, errors.As(err, &typeErr), "Decoders should be scoped to the decoder they were registered with")
+	assert.Equal(t, typeErr.Key, "Hue", "Without the decoder color should be read as an int")
+}
+
+func TestRegisterDecoderError(t *testing.T) {
+
+	var settings struct{ Hue color }
+
+	decoder := NewDecoder(strings.NewReader("hue=green"))
+	decoder.RegisterDecoder(reflect.TypeOf(color(0)), func(text string) (interface{}, error) {
+		return nil, errors.New("unknown color")
+	})
+
+	err := decoder.Decode(&settings)
+
+	var typeErr *UnmarshalTypeError
+	assert.True(t, errors.As(err, &typeErr), "Decoder errors should be UnmarshalTypeErrors")
+	assert.Equal(t, typeErr.Err.Error(), "unknown color", "Decoder error should be kept")
+
+	decoder = NewDecoder(strings.NewReader("hue=green"))
+	decoder.RegisterDecoder(reflect.TypeOf(color(0)), func(text string) (interface{}, error) {
+		return "green", nil
+	})
+
+	assert.NotNil(t, decoder.Decode(&settings), "Decoder returning the wrong type should be an error")
+}
+
+func TestDecodeSection(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader("[s]\nch=1\nport=80\n[s.inner]\nx=2"))
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	var settings struct {
+		Ch    chan int
+		Port  int
+		Inner struct{ X int }
+	}
+
+	assert.True(t, config.DataFromSection("s", &settings), "DataFromSection should skip the channel")
+
+	err = NewDecoder(nil).DecodeSection(config, "s", &settings)
+
+	assert.True(t, errors.Is(err, ErrUnsupportedType), "DecodeSection should report the unsupported field")
+	assert.Equal(t, settings.Port, 80, "DecodeSection should read the other fields")
+	assert.Equal(t, settings.Inner.X, 2, "DecodeSection should read nested sections")
+
+	err = NewDecoder(nil).DecodeSection(config, "missing", &settings)
+	assert.True(t, errors.Is(err, ErrNotFound), "Missing section should be not found")
+}
@@ -0,0 +1,220 @@
+package mini
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+/*
+AES256GCM is the algorithm used by KeyFile, written in encrypted values as ENC[AES256_GCM,...].
+*/
+const AES256GCM = "AES256_GCM"
+
+/*
+EncryptedValue is a value written in a file as ENC[algorithm,data:...,iv:...,tag:...], with the data,
+iv and tag in base64.
+*/
+type EncryptedValue struct {
+	Algorithm string
+	Data      []byte
+	IV        []byte
+	Tag       []byte
+}
+
+/*
+ParseEncrypted reads an encrypted value, it returns false if text isn't of the form ENC[...].
+*/
+func ParseEncrypted(text string) (EncryptedValue, bool, error) {
+	var enc EncryptedValue
+
+	if !isEncrypted(text) {
+		return enc, false, nil
+	}
+
+	fields := strings.Split(text[len("ENC["):len(text)-1], ",")
+	enc.Algorithm = fields[0]
+
+	for _, field := range fields[1:] {
+		name, data, ok := strings.Cut(field, ":")
+
+		if !ok {
+			return enc, true, fmt.Errorf("mini: invalid encrypted value, %q has no name", field)
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(data)
+
+		if err != nil {
+			return enc, true, fmt.Errorf("mini: invalid encrypted value, %s: %w", name, err)
+		}
+
+		switch name {
+		case "data":
+			enc.Data = decoded
+		case "iv":
+			enc.IV = decoded
+		case "tag":
+			enc.Tag = decoded
+		}
+	}
+
+	return enc, true, nil
+}
+
+//Return true if text is of the form ENC[...]
+func isEncrypted(text string) bool {
+	return strings.HasPrefix(text, "ENC[") && strings.HasSuffix(text, "]")
+}
+
+/*
+String returns the value in the form it is written in a file.
+*/
+func (enc EncryptedValue) String() string {
+	encode := base64.StdEncoding.EncodeToString
+	return "ENC[" + enc.Algorithm + ",data:" + encode(enc.Data) + ",iv:" + encode(enc.IV) + ",tag:" + encode(enc.Tag) + "]"
+}
+
+/*
+Decrypter decrypts the values in a file. Path is the section and key of the value, as section.key or key
+for global values. The key is in lower case, the section is as written unless Options.CaseInsensitiveSections is set,
+when it is in lower case too. Implementations should authenticate it, so a value can't be moved to another key.
+*/
+type Decrypter interface {
+	Decrypt(enc EncryptedValue, path string) (string, error)
+}
+
+/*
+Encrypter encrypts values for a Decrypter, see Document.Encrypt.
+*/
+type Encrypter interface {
+	Encrypt(text string, path string) (EncryptedValue, error)
+}
+
+/*
+KeyFile is an Encrypter and Decrypter that uses AES-256-GCM with a single key.
+*/
+type KeyFile struct {
+	key []byte
+}
+
+/*
+NewKey returns a new random key, in the hex form read by LoadKeyFile.
+*/
+func NewKey() (string, error) {
+	key := make([]byte, 32)
+
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(key), nil
+}
+
+/*
+LoadKeyFile reads a key written by NewKey, 64 hex digits, from the file at path.
+*/
+func LoadKeyFile(path string) (*KeyFile, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("mini: %s is not a key, keys are 64 hex digits", path)
+	}
+
+	return &KeyFile{key: key}, nil
+}
+
+func (keyFile *KeyFile) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(keyFile.key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func (keyFile *KeyFile) Encrypt(text string, path string) (EncryptedValue, error) {
+	aead, err := keyFile.aead()
+
+	if err != nil {
+		return EncryptedValue{}, err
+	}
+
+	iv := make([]byte, aead.NonceSize())
+
+	if _, err := rand.Read(iv); err != nil {
+		return EncryptedValue{}, err
+	}
+
+	sealed := aead.Seal(nil, iv, []byte(text), []byte(path))
+	split := len(sealed) - aead.Overhead()
+
+	return EncryptedValue{Algorithm: AES256GCM, Data: sealed[:split], IV: iv, Tag: sealed[split:]}, nil
+}
+
+func (keyFile *KeyFile) Decrypt(enc EncryptedValue, path string) (string, error) {
+	if enc.Algorithm != AES256GCM {
+		return "", fmt.Errorf("mini: unknown encryption %q", enc.Algorithm)
+	}
+
+	aead, err := keyFile.aead()
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(enc.IV) != aead.NonceSize() || len(enc.Tag) != aead.Overhead() {
+		return "", errors.New("mini: invalid encrypted value, bad iv or tag")
+	}
+
+	text, err := aead.Open(nil, enc.IV, append(append([]byte(nil), enc.Data...), enc.Tag...), []byte(path))
+
+	if err != nil {
+		return "", errors.New("mini: can't decrypt value, the key or path is wrong or the value was changed")
+	}
+
+	return string(text), nil
+}
+
+//Return the path of a key used to authenticate its encrypted values, the section is named the way options store it
+func encryptionPath(options *Options, sectionName string, key string) string {
+	if len(sectionName) == 0 {
+		return strings.ToLower(key)
+	}
+	return options.sectionKey(sectionName) + "." + strings.ToLower(key)
+}
+
+//Return the decrypted value of an ENC[...] value, or nil if raw isn't encrypted
+func (src *source) decrypt(raw string, sectionName string, key string, line int) (*value, error) {
+	enc, ok, err := ParseEncrypted(raw)
+
+	if !ok {
+		return nil, nil
+	}
+
+	path := encryptionPath(src.options, sectionName, key)
+
+	if err == nil {
+		var text string
+
+		if text, err = src.options.Decrypter.Decrypt(enc, path); err == nil {
+			val := newValue(escapeText(text), line, src)
+			val.markSecret()
+			return val, nil
+		}
+	}
+
+	return nil, fmt.Errorf("mini: can't decrypt %s at %s: %w", path, Position{File: src.file, Line: line}, err)
+}
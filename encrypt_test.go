@@ -0,0 +1,142 @@
+package mini
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKeyFile(t *testing.T) *KeyFile {
+	key, err := NewKey()
+	assert.Nil(t, err, "NewKey should not return an error.")
+
+	path := filepath.Join(t.TempDir(), "key")
+	assert.Nil(t, os.WriteFile(path, []byte(key+"\n"), 0600), "Key file should be written")
+
+	keyFile, err := LoadKeyFile(path)
+	assert.Nil(t, err, "LoadKeyFile should read the key.")
+	return keyFile
+}
+
+//Encrypt keys in ini and return the result
+func encryptKeys(t *testing.T, ini string, keyFile *KeyFile, paths ...[2]string) string {
+	doc, err := ParseDocument(strings.NewReader(ini))
+	assert.Nil(t, err, "ParseDocument should not return an error.")
+
+	for _, path := range paths {
+		found, err := doc.Encrypt(path[0], path[1], keyFile)
+		assert.True(t, found, "Key should be found: "+path[1])
+		assert.Nil(t, err, "Encrypt should not return an error.")
+	}
+
+	var buf bytes.Buffer
+	doc.WriteTo(&buf)
+	return buf.String()
+}
+
+func TestEncryptedValues(t *testing.T) {
+
+	ini := `
+# credentials
+[db]
+user = admin
+password = "hunter 2"
+ports[] = 5432
+ports[] = 5433
+`
+	keyFile := testKeyFile(t)
+	encrypted := encryptKeys(t, ini, keyFile, [2]string{"db", "password"}, [2]string{"db", "ports"})
+
+	assert.True(t, strings.Contains(encrypted, "# credentials\n[db]\nuser = admin\npassword = ENC[AES256_GCM,"), "Other lines should be kept: "+encrypted)
+	assert.False(t, strings.Contains(encrypted, "hunter"), "Value should be encrypted: "+encrypted)
+	assert.Equal(t, encryptKeys(t, encrypted, keyFile, [2]string{"db", "password"}), encrypted, "Encrypted values should not be encrypted again")
+
+	config := &Config{Options: Options{Decrypter: keyFile}}
+	assert.Nil(t, config.InitializeFromReader(strings.NewReader(encrypted)), "Encrypted file should load.")
+
+	assert.Equal(t, config.StringFromSection("db", "password", ""), "hunter 2", "Value should be decrypted")
+	assert.Equal(t, config.IntegersFromSection("db", "ports"), []int64{5432, 5433}, "Array entries should be decrypted")
+
+	password, _ := config.Lookup("db", "password")
+	assert.True(t, password.Secret(), "Decrypted values should be secret")
+
+	plain, err := LoadConfigurationFromReader(strings.NewReader(encrypted))
+	assert.Nil(t, err, "Encrypted file should load without a Decrypter.")
+	assert.True(t, strings.HasPrefix(plain.StringFromSection("db", "password", ""), "ENC["), "Values should be text without a Decrypter")
+}
+
+func TestEncryptedValueErrors(t *testing.T) {
+
+	keyFile := testKeyFile(t)
+	encrypted := encryptKeys(t, "[db]\npassword=secret\n", keyFile, [2]string{"db", "password"})
+
+	load := func(ini string, decrypter Decrypter) error {
+		config := &Config{Options: Options{Decrypter: decrypter}}
+		return config.InitializeFromReader(strings.NewReader(ini))
+	}
+
+	moved := strings.Replace(encrypted, "password", "other", 1)
+	assert.NotNil(t, load(moved, keyFile), "Value moved to another key should not decrypt")
+
+	assert.NotNil(t, load(encrypted, testKeyFile(t)), "Value should not decrypt with another key")
+
+	upper := encryptKeys(t, "[DB]\npassword=secret\n", keyFile, [2]string{"DB", "password"})
+	assert.Nil(t, load(upper, keyFile), "Value should decrypt in the section it was encrypted in")
+	assert.NotNil(t, load(strings.Replace(upper, "[DB]", "[db]", 1), keyFile), "Value moved to a section spelled differently should not decrypt")
+
+	//with CaseInsensitiveSections the spelling of the section doesn't matter, as long as both sides use the option
+	doc, err := ParseDocument(strings.NewReader("[DB]\npassword=secret\n"))
+	assert.Nil(t, err, "ParseDocument should not return an error.")
+	doc.SetOptions(Options{CaseInsensitiveSections: true})
+	doc.Encrypt("DB", "password", keyFile)
+
+	config := &Config{Options: Options{Decrypter: keyFile, CaseInsensitiveSections: true}}
+	assert.Nil(t, config.InitializeFromReader(strings.NewReader(strings.Replace(writeDocument(t, doc), "[DB]", "[db]", 1))), "Value should decrypt in [db] with CaseInsensitiveSections")
+	assert.NotNil(t, load(writeDocument(t, doc), keyFile), "Value encrypted with CaseInsensitiveSections should need the option to decrypt in [DB]")
+
+	enc, ok, err := ParseEncrypted(encrypted[len("[db]\npassword=") : len(encrypted)-1])
+	assert.True(t, ok, "Value should be encrypted")
+	assert.Nil(t, err, "ParseEncrypted should not return an error.")
+
+	enc.Data[0] ^= 1
+	err = load("[db]\npassword="+enc.String()+"\n", keyFile)
+	assert.NotNil(t, err, "Changed value should not decrypt")
+	assert.True(t, strings.Contains(err.Error(), "db.password at line 2"), "Error should name the key: "+err.Error())
+
+	assert.NotNil(t, load("key=ENC[AES256_GCM,data:%%%]\n", keyFile), "Invalid base64 should be an error")
+	assert.NotNil(t, load("key=ENC[ROT13,data:,iv:,tag:]\n", keyFile), "Unknown algorithm should be an error")
+}
+
+func TestEncryptExtendedSection(t *testing.T) {
+
+	ini := "[base]\nuser=admin\n[prod : base]\npassword=secret\n"
+	keyFile := testKeyFile(t)
+
+	load := func(encrypted string, extends bool) *Config {
+		config := &Config{Options: Options{Decrypter: keyFile, Extends: extends}}
+		assert.Nil(t, config.InitializeFromReader(strings.NewReader(encrypted)), "Encrypted file should load.")
+		return config
+	}
+
+	//without Options.Extends the section is named as written, as the loader names it
+	encrypted := encryptKeys(t, ini, keyFile, [2]string{"prod : base", "password"})
+	assert.Equal(t, load(encrypted, false).StringFromSection("prod : base", "password", ""), "secret", "Value should decrypt without Extends")
+
+	doc, err := ParseDocument(strings.NewReader(ini))
+	assert.Nil(t, err, "ParseDocument should not return an error.")
+
+	found, _ := doc.Encrypt("prod", "password", keyFile)
+	assert.False(t, found, "Section prod should only be found with Extends")
+
+	doc.SetOptions(Options{Extends: true})
+	found, err = doc.Encrypt("prod", "password", keyFile)
+	assert.True(t, found, "Section prod should be found with Extends")
+	assert.Nil(t, err, "Encrypt should not return an error.")
+
+	config := load(writeDocument(t, doc), true)
+	assert.Equal(t, config.StringFromSection("prod", "password", ""), "secret", "Value should decrypt with Extends")
+	assert.Equal(t, config.StringFromSection("prod", "user", ""), "admin", "Section should extend base")
+}
@@ -0,0 +1,269 @@
+package mini
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+/*
+ToMap returns the config as a map. Global values are stored under their keys and each section is stored
+under its name as a map[string]interface{} of its own values. Dotted section names, like server.http, are
+kept as a single key.
+
+Scalar values are strings, array values are []string and map values are map[string]string.
+Keys and section names are spelled the way they were first written. A global key with the same name as a
+section can't be stored alongside it, so it is an error.
+*/
+func (config *Config) ToMap() (map[string]interface{}, error) {
+
+	out := sectionToMap(&(config.configSection))
+
+	for _, section := range config.sectionOrder {
+		if _, ok := out[section.name]; ok {
+			return nil, fmt.Errorf("mini: global key %q has the same name as a section, so the map can't hold both", section.name)
+		}
+
+		out[section.name] = sectionToMap(section)
+	}
+
+	return out, nil
+}
+
+func sectionToMap(section *configSection) map[string]interface{} {
+	out := make(map[string]interface{}, len(section.order))
+
+	for _, key := range section.order {
+		out[key] = Value{section.own(key)}.plain()
+	}
+
+	return out
+}
+
+//Return the value as a string, []string or map[string]string
+func (val Value) plain() interface{} {
+	switch val.Kind() {
+	case ArrayValue:
+		list := make([]string, val.Len())
+		for i := range list {
+			list[i] = val.Index(i).String()
+		}
+		return list
+	case MapValue:
+		names := val.Names()
+		m := make(map[string]string, len(names))
+		for _, name := range names {
+			entry, _ := val.Get(name)
+			m[name] = entry.String()
+		}
+		return m
+	}
+	return val.String()
+}
+
+/*
+FromMap initializes the config from a map, such as one decoded from JSON, YAML or TOML.
+
+A map of a scalar type, like the map[string]string returned by ToMap, becomes a map value, written in ini as
+key[name]=value. Other maps, like a map[string]interface{}, become sections: at the top level they are sections
+and inside a section they are nested sections, so {"server": {"http": {"port": 80}}} sets port in [server.http].
+Slices of scalars become array values and other values become global values or values of their section.
+
+Scalars are stored as text, numbers and booleans are formatted with strconv and times use RFC3339.
+Values that ini can't represent, like arrays of tables, nested arrays or nil, are an error.
+Map keys are read in sorted order.
+*/
+func (config *Config) FromMap(m map[string]interface{}) error {
+
+	config.configSection.init(config.name)
+	config.sections = make(map[string]*configSection)
+	config.sectionOrder = nil
+	config.src = &source{options: &config.Options}
+
+	root := reflect.ValueOf(m)
+
+	for _, key := range sortedMapKeys(root) {
+		val := elem(root.MapIndex(key))
+
+		if isSectionMap(val) {
+			if err := config.sectionFromMap(keyString(key), val); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := config.valueFromMap(&(config.configSection), keyString(key), keyString(key), val); err != nil {
+			return err
+		}
+	}
+
+	return config.linkSections()
+}
+
+func (config *Config) sectionFromMap(sectionName string, m reflect.Value) error {
+
+	section := config.addSection(sectionName)
+
+	for _, key := range sortedMapKeys(m) {
+		val := elem(m.MapIndex(key))
+		path := sectionName + "." + keyString(key)
+
+		if isSectionMap(val) {
+			if err := config.sectionFromMap(path, val); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := config.valueFromMap(section, keyString(key), path, val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//Check if a map becomes a section, rather than a map value, which can only hold scalars
+func isSectionMap(val reflect.Value) bool {
+	if val.Kind() != reflect.Map {
+		return false
+	}
+
+	switch val.Type().Elem().Kind() {
+	case reflect.Interface, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+//Store a scalar, slice or map of scalars in section, path names the value in errors
+func (config *Config) valueFromMap(section *configSection, key string, path string, val reflect.Value) error {
+
+	var stored *value
+
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		if val.Type().Elem().Kind() == reflect.Uint8 { //[]byte is text
+			break
+		}
+
+		stored = &value{src: config.src, more: &composite{kind: ArrayValue, entries: make([]*value, 0)}}
+
+		for i := 0; i < val.Len(); i++ {
+			entry, err := config.scalarFromMap(elem(val.Index(i)), path+"["+strconv.Itoa(i)+"]")
+
+			if err != nil {
+				return err
+			}
+
+			stored.more.entries = append(stored.more.entries, entry)
+		}
+
+		if len(stored.more.entries) > 0 {
+			stored.raw = stored.more.entries[0].raw
+		}
+	case reflect.Map:
+		stored = &value{src: config.src, more: &composite{kind: MapValue, entries: make([]*value, 0)}}
+
+		for _, name := range sortedMapKeys(val) {
+			entry, err := config.scalarFromMap(elem(val.MapIndex(name)), path+"["+keyString(name)+"]")
+
+			if err != nil {
+				return err
+			}
+
+			stored = stored.setEntry(keyString(name), entry)
+		}
+	}
+
+	if stored == nil {
+		var err error
+		stored, err = config.scalarFromMap(val, path)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	lower := string(appendLower(nil, key))
+	section.set(key, lower, section.values[lower], stored)
+	return nil
+}
+
+func (config *Config) scalarFromMap(val reflect.Value, path string) (*value, error) {
+
+	var text string
+
+	switch val.Kind() {
+	case reflect.String:
+		text = val.String()
+	case reflect.Bool:
+		text = strconv.FormatBool(val.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		text = strconv.FormatInt(val.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		text = strconv.FormatUint(val.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		text = strconv.FormatFloat(val.Float(), 'g', -1, val.Type().Bits())
+	case reflect.Slice:
+		if val.Type().Elem().Kind() != reflect.Uint8 {
+			return nil, fmt.Errorf("mini: %s: nested arrays can't be represented in ini", path)
+		}
+		text = string(val.Bytes())
+	case reflect.Map:
+		return nil, fmt.Errorf("mini: %s: arrays of tables can't be represented in ini", path)
+	case reflect.Invalid:
+		return nil, fmt.Errorf("mini: %s: null can't be represented in ini", path)
+	default:
+		switch v := val.Interface().(type) {
+		case time.Time:
+			text = v.Format(time.RFC3339Nano)
+		case fmt.Stringer:
+			text = v.String()
+		default:
+			return nil, fmt.Errorf("mini: %s: %s can't be represented in ini", path, val.Type())
+		}
+	}
+
+	return new(value).initScalar(escapeText(text), 0, config.src), nil
+}
+
+//Return text in the form it would be written in a file, so that the value read back is text
+func escapeText(text string) string {
+	for i := 0; i < len(text); i++ {
+		if c := text[i]; c == '\\' || c == '"' || c < ' ' || c == 0x7f {
+			quoted := strconv.Quote(text)
+			return quoted[1 : len(quoted)-1]
+		}
+	}
+	return text
+}
+
+//Unwrap interfaces and pointers
+func elem(val reflect.Value) reflect.Value {
+	for val.Kind() == reflect.Interface || val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return reflect.Value{}
+		}
+
+		val = val.Elem()
+	}
+	return val
+}
+
+func sortedMapKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keyString(keys[i]) < keyString(keys[j])
+	})
+
+	return keys
+}
+
+//Return a map key as text, maps decoded from YAML can have keys that aren't strings
+func keyString(key reflect.Value) string {
+	return fmt.Sprint(key.Interface())
+}
@@ -0,0 +1,121 @@
+package mini
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+const exportIni = `Name="a\tb"
+hosts[]=a
+hosts[]=b
+
+[Server.http]
+Port=80
+headers[Accept]=text/html`
+
+func TestToMap(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(exportIni))
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	m := toMap(t, config)
+
+	assert.Equal(t, m["Name"], "a\tb", "Scalar should be decoded text")
+	assert.Equal(t, m["hosts"], []string{"a", "b"}, "Array should be a []string")
+
+	section, ok := m["Server.http"].(map[string]interface{})
+	assert.True(t, ok, "Section should be a map")
+	assert.Equal(t, section["Port"], "80", "Read port wrong")
+	assert.Equal(t, section["headers"], map[string]string{"Accept": "text/html"}, "Map should be a map[string]string")
+}
+
+func toMap(t *testing.T, config *Config) map[string]interface{} {
+	m, err := config.ToMap()
+	assert.Nil(t, err, "ToMap should not return an error.")
+	return m
+}
+
+func TestToMapCollision(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader("server=1\n[server]\nport=80"))
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	_, err = config.ToMap()
+	assert.NotNil(t, err, "Global key with the name of a section should be an error.")
+}
+
+func TestFromMap(t *testing.T) {
+
+	config := new(Config)
+	err := config.FromMap(map[string]interface{}{
+		"name":  "say \"hi\"",
+		"count": 3,
+		"ratio": 1.5,
+		"debug": true,
+		"when":  time.Date(2015, 1, 2, 3, 4, 5, 0, time.UTC),
+		"hosts": []interface{}{"a", "b"},
+		"server": map[string]interface{}{
+			"host": "example.com",
+			"http": map[string]interface{}{
+				"port":    80,
+				"headers": map[string]string{"Accept": "text/html"},
+				"tls":     map[string]interface{}{"on": true, "ciphers": []string{"x"}},
+			},
+		},
+	})
+
+	assert.Nil(t, err, "FromMap should not return an error.")
+	assert.Equal(t, config.String("name", ""), "say \"hi\"", "Read name wrong")
+	assert.Equal(t, config.Integer("count", 0), int64(3), "Read count wrong")
+	assert.Equal(t, config.Float("ratio", 0), 1.5, "Read ratio wrong")
+	assert.Equal(t, config.Boolean("debug", false), true, "Read debug wrong")
+	assert.Equal(t, config.String("when", ""), "2015-01-02T03:04:05Z", "Read when wrong")
+	assert.Equal(t, config.Strings("hosts"), []string{"a", "b"}, "Read hosts wrong")
+	assert.Equal(t, config.StringFromSection("server", "host", ""), "example.com", "Read host wrong")
+	assert.Equal(t, config.IntegerFromSection("server.http", "port", 0), int64(80), "Nested map should be a nested section")
+	assert.Equal(t, config.MapFromSection("server.http", "headers"), map[string]string{"Accept": "text/html"}, "Map of strings should be a map value")
+	assert.Equal(t, config.StringsFromSection("server.http.tls", "ciphers"), []string{"x"}, "Nested map should be a nested section")
+	assert.Equal(t, config.SectionsInOrder(), []string{"server", "server.http", "server.http.tls"}, "Sections should be added in sorted order")
+}
+
+func TestFromMapErrors(t *testing.T) {
+
+	bad := []map[string]interface{}{
+		{"s": map[string]interface{}{"rules": []interface{}{map[string]interface{}{"a": 1}}}},
+		{"list": []interface{}{[]interface{}{1}}},
+		{"missing": nil},
+		{"c": complex(1, 2)},
+	}
+
+	for _, m := range bad {
+		err := new(Config).FromMap(m)
+		assert.NotNil(t, err, "FromMap should return an error for a value ini can't hold.")
+	}
+}
+
+func TestMapRoundTrip(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(exportIni))
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	copied := new(Config)
+	err = copied.FromMap(toMap(t, config))
+
+	assert.Nil(t, err, "FromMap should not return an error.")
+	assert.Equal(t, toMap(t, copied), toMap(t, config), "Round trip should keep every value")
+	assert.Equal(t, copied.String("name", ""), "a\tb", "Round trip should keep escapes")
+
+	//a global map value and a section have to come back as they were, not as each other
+	config, err = LoadConfigurationFromReader(strings.NewReader("env[HOME]=/root\n[labels]\nteam=core"))
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	copied = new(Config)
+	err = copied.FromMap(toMap(t, config))
+
+	assert.Nil(t, err, "FromMap should not return an error.")
+	assert.Equal(t, toMap(t, copied), toMap(t, config), "Round trip should keep maps and sections apart")
+	assert.Equal(t, copied.Map("env"), map[string]string{"HOME": "/root"}, "Global map should stay a map value")
+	assert.Equal(t, copied.StringFromSection("labels", "team", ""), "core", "Section should stay a section")
+}
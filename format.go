@@ -0,0 +1,266 @@
+package mini
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+)
+
+/*
+KeyCase chooses how Format spells keys.
+*/
+type KeyCase int
+
+const (
+	//KeysAsWritten keeps each key as it was written
+	KeysAsWritten KeyCase = iota
+	//KeysLower writes every key in lower case
+	KeysLower
+	//KeysFirstSpelling writes every use of a key in a section the way it was first written
+	KeysFirstSpelling
+)
+
+/*
+QuoteStyle chooses how Format writes quoted values.
+*/
+type QuoteStyle int
+
+const (
+	//QuoteAsWritten keeps quotes as they were written
+	QuoteAsWritten QuoteStyle = iota
+	//QuoteWhenNeeded removes quotes that don't change the value, those that are kept become double quotes
+	QuoteWhenNeeded
+	//QuoteDouble changes single quotes to double quotes
+	QuoteDouble
+)
+
+/*
+FormatOptions controls the output of Format. The zero value writes key=value with no indentation,
+keeps keys and quotes as written and keeps the order of the file.
+
+Format always trims white space from the ends of lines, writes one blank line between sections and
+collapses runs of blank lines.
+*/
+type FormatOptions struct {
+	SpaceAroundEquals bool       //write key = value rather than key=value
+	Indent            string     //written before the keys and comments in a section
+	KeyCase           KeyCase    //how keys are spelled
+	Quotes            QuoteStyle //how quoted values are written
+	SortKeys          bool       //sort the keys in each section, keeping each key's comments with it
+	MergeSections     bool       //move the keys in a split section to the first place the section appears
+}
+
+/*
+Format reads an ini file from input and writes it to output in a canonical form.
+Comments and blank lines are kept, comments stay with the key or section that follows them.
+The file's byte order mark or UTF-16 encoding, its line endings and whether it ends with a line ending are kept.
+*/
+func Format(input io.Reader, output io.Writer, options FormatOptions) error {
+
+	doc, err := ParseDocument(input)
+
+	if err != nil {
+		return err
+	}
+
+	return doc.Format(output, options)
+}
+
+/*
+Format writes the document to output in the canonical form described by options, in the encoding and with
+the line endings it was read with, like WriteTo.
+*/
+func (doc *Document) Format(output io.Writer, options FormatOptions) error {
+
+	f := &formatter{
+		options:   options,
+		newline:   doc.newline,
+		start:     true,
+		spellings: make(map[string]map[string]string),
+	}
+
+	for _, section := range doc.formatSections(options) {
+		f.section(section, section.key(&doc.options))
+	}
+
+	if f.wrote && doc.finalNewline {
+		f.text.WriteString(f.newline)
+	}
+
+	_, err := output.Write(doc.encoding.encode(f.text.Bytes()))
+	return err
+}
+
+//Return the sections to format, merging and sorting them if the options ask for it
+func (doc *Document) formatSections(options FormatOptions) []*docSection {
+
+	sections := make([]*docSection, 0, len(doc.sections))
+	merged := make(map[string]*docSection)
+
+	for _, section := range doc.sections {
+		copied := *section
+		copied.entries = append([]*docEntry(nil), section.entries...)
+		key := section.key(&doc.options)
+
+		if first, ok := merged[key]; ok && options.MergeSections {
+			first.merge(&copied)
+			continue
+		}
+
+		merged[key] = &copied
+		sections = append(sections, &copied)
+	}
+
+	if options.SortKeys {
+		for _, section := range sections {
+			sort.SliceStable(section.entries, func(i, j int) bool {
+				return strings.ToLower(section.entries[i].line.Key) < strings.ToLower(section.entries[j].line.Key)
+			})
+		}
+	}
+
+	return sections
+}
+
+//Add the entries of a later occurrence of the same section, the comments around its header go with its first entry
+func (section *docSection) merge(other *docSection) {
+
+	comments := append(append([]docLine(nil), section.trailing...), other.comments...)
+	section.trailing = nil
+
+	if len(other.entries) == 0 {
+		section.trailing = append(comments, other.trailing...)
+		return
+	}
+
+	first := *other.entries[0]
+	first.comments = append(comments, first.comments...)
+
+	section.entries = append(section.entries, &first)
+	section.entries = append(section.entries, other.entries[1:]...)
+	section.trailing = other.trailing
+}
+
+//The state of a Format call
+type formatter struct {
+	text      bytes.Buffer
+	options   FormatOptions
+	newline   string                       //written between lines
+	wrote     bool                         //a line has been written
+	blank     bool                         //a blank line should be written before the next line
+	start     bool                         //nothing has been written in the current section
+	spellings map[string]map[string]string //the first spelling of each key, by section
+}
+
+//Write a section, key is its normalized name, which finds the spellings used in its other occurrences
+func (f *formatter) section(section *docSection, key string) {
+
+	indent := ""
+
+	if section.header != nil {
+		indent = f.options.Indent
+		f.blank = true
+		f.comments(section.comments, "")
+		f.line("", "["+strings.TrimSpace(section.header.Section)+"]")
+	}
+
+	f.start = true
+	spellings := f.spellings[key]
+
+	if spellings == nil {
+		spellings = make(map[string]string)
+		f.spellings[key] = spellings
+	}
+
+	for _, entry := range section.entries {
+		f.comments(entry.comments, indent)
+		f.line(indent, f.entry(&entry.line, spellings))
+	}
+
+	f.comments(section.trailing, indent)
+}
+
+func (f *formatter) comments(lines []docLine, indent string) {
+	for _, line := range lines {
+		if line.Kind == Comment {
+			f.line(indent, line.Text)
+		} else if !f.start && !f.options.SortKeys {
+			f.blank = true
+		}
+	}
+}
+
+//Write a line, the line ending before it is written here so the last line only gets one if the file had it
+func (f *formatter) line(indent string, text string) {
+	if f.wrote {
+		f.text.WriteString(f.newline)
+
+		if f.blank {
+			f.text.WriteString(f.newline)
+		}
+	}
+
+	f.text.WriteString(indent)
+	f.text.WriteString(text)
+	f.wrote, f.blank, f.start = true, false, false
+}
+
+//Return the formatted text of a key
+func (f *formatter) entry(line *docLine, spellings map[string]string) string {
+
+	key := line.Key
+	lower := strings.ToLower(key)
+
+	switch f.options.KeyCase {
+	case KeysLower:
+		key = lower
+	case KeysFirstSpelling:
+		if first, ok := spellings[lower]; ok {
+			key = first
+		} else {
+			spellings[lower] = key
+		}
+	}
+
+	switch line.Kind {
+	case ArrayItem:
+		key += "[]"
+	case MapItem:
+		key += "[" + line.Name + "]"
+	}
+
+	value := formatQuotes(line.rawValue(), f.options.Quotes)
+
+	if !f.options.SpaceAroundEquals {
+		return key + "=" + value
+	}
+
+	if len(value) == 0 {
+		return key + " ="
+	}
+
+	return key + " = " + value
+}
+
+//Change the quotes around a value, the value read from the result is the same as the value read from raw
+func formatQuotes(raw string, style QuoteStyle) string {
+
+	inner := trimQuotes(raw)
+
+	if style == QuoteAsWritten || len(inner) == len(raw) {
+		return raw
+	}
+
+	needed := len(inner) == 0 || len(strings.TrimSpace(inner)) != len(inner) || trimQuotes(inner) != inner
+
+	if style == QuoteWhenNeeded && !needed {
+		return inner
+	}
+
+	if raw[0] == '\'' && !strings.ContainsAny(inner, "\"\\") {
+		return "\"" + inner + "\""
+	}
+
+	return raw
+}
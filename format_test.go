@@ -0,0 +1,112 @@
+package mini
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func format(t *testing.T, ini string, options FormatOptions) string {
+	var buf bytes.Buffer
+	err := Format(strings.NewReader(ini), &buf, options)
+	assert.Nil(t, err, "Format should not return an error.")
+	return buf.String()
+}
+
+func TestFormatDefault(t *testing.T) {
+
+	ini := "\n\n  name =  value  \n[ section ]\n\n\n# about b\nb=2\n\n\n\na = 1\n[other]\nc=3\n\n"
+
+	assert.Equal(t, format(t, ini, FormatOptions{}), "name=value\n\n[section]\n# about b\nb=2\n\na=1\n\n[other]\nc=3\n", "Default format wrong")
+}
+
+func TestFormatSpacingAndIndent(t *testing.T) {
+
+	ini := "a=1\nempty=\n[section]\n; note\nb=2"
+	options := FormatOptions{SpaceAroundEquals: true, Indent: "  "}
+
+	assert.Equal(t, format(t, ini, options), "a = 1\nempty =\n\n[section]\n  ; note\n  b = 2", "Spaced format wrong")
+}
+
+func TestFormatSortKeys(t *testing.T) {
+
+	ini := "[section]\n# about c\nc=3\nlist[]=2\n\n# about a\nA=1\nlist[]=1"
+
+	assert.Equal(t, format(t, ini, FormatOptions{SortKeys: true}), "[section]\n# about a\nA=1\n# about c\nc=3\nlist[]=2\nlist[]=1", "Sorted format wrong")
+}
+
+func TestFormatMergeSections(t *testing.T) {
+
+	ini := "[a]\nx=1\n[b]\ny=2\n# about a again\n[a]\nz=3"
+
+	assert.Equal(t, format(t, ini, FormatOptions{MergeSections: true}), "[a]\nx=1\n# about a again\nz=3\n\n[b]\ny=2", "Merged format wrong")
+	assert.Equal(t, format(t, ini, FormatOptions{}), "[a]\nx=1\n\n[b]\ny=2\n\n# about a again\n[a]\nz=3", "Split sections should stay split")
+}
+
+func TestFormatKeyCase(t *testing.T) {
+
+	ini := "MaxConnections=1\n[s]\nHeaders[Accept]=a\nheaders[Host]=b"
+
+	assert.Equal(t, format(t, ini, FormatOptions{KeyCase: KeysLower}), "maxconnections=1\n\n[s]\nheaders[Accept]=a\nheaders[Host]=b", "Lower case keys wrong")
+	assert.Equal(t, format(t, ini, FormatOptions{KeyCase: KeysFirstSpelling}), "MaxConnections=1\n\n[s]\nHeaders[Accept]=a\nHeaders[Host]=b", "First spelling keys wrong")
+}
+
+func TestFormatQuotes(t *testing.T) {
+
+	ini := `a="plain"
+b='single'
+c=" padded "
+d=""
+e='say "hi"'
+f="a", "b"`
+
+	assert.Equal(t, format(t, ini, FormatOptions{Quotes: QuoteWhenNeeded}), "a=plain\nb=single\nc=\" padded \"\nd=\"\"\ne=say \"hi\"\nf=\"a\", \"b\"", "Needed quotes wrong")
+	assert.Equal(t, format(t, ini, FormatOptions{Quotes: QuoteDouble}), "a=\"plain\"\nb=\"single\"\nc=\" padded \"\nd=\"\"\ne='say \"hi\"'\nf=\"a\", \"b\"", "Double quotes wrong")
+}
+
+func TestFormatKeepsEncoding(t *testing.T) {
+
+	assert.Equal(t, format(t, "\ufeffa = 1\r\n[s]\r\nb=2\r\n", FormatOptions{}), "\ufeffa=1\r\n\r\n[s]\r\nb=2\r\n", "Byte order mark and CRLF should be kept")
+	assert.Equal(t, format(t, "a=1\n\n\nb=2\n", FormatOptions{}), "a=1\n\nb=2\n", "Final line ending should be kept")
+
+	ini := encodeUTF16("[s]\r\nb = \u00e9\r\n", binary.BigEndian)
+	assert.Equal(t, []byte(format(t, string(ini), FormatOptions{})), encodeUTF16("[s]\r\nb=\u00e9\r\n", binary.BigEndian), "UTF-16 should be written as UTF-16")
+}
+
+func TestFormatKeepsValues(t *testing.T) {
+
+	ini := `# comment
+first="a b"
+ Second = 'two'
+
+[b]
+list[]=1
+; about x
+x = "y"
+headers[Accept]=text/html
+[a]
+A=1
+[b]
+list[]=2`
+
+	options := FormatOptions{SpaceAroundEquals: true, Indent: "\t", KeyCase: KeysLower, Quotes: QuoteWhenNeeded, SortKeys: true, MergeSections: true}
+	formatted := format(t, ini, options)
+
+	before, err := LoadConfigurationFromReader(strings.NewReader(ini))
+	assert.Nil(t, err, "Configuration should load without error.")
+	after, err := LoadConfigurationFromReader(strings.NewReader(formatted))
+	assert.Nil(t, err, "Formatted configuration should load without error.")
+
+	for _, section := range before.SectionNames() {
+		for _, key := range before.KeysForSection(section) {
+			a, _ := before.Lookup(section, key)
+			b, ok := after.Lookup(section, key)
+			assert.True(t, ok, "Formatted configuration should have "+section+"."+key)
+			assert.Equal(t, b.Raw(), a.Raw(), "Formatting should not change "+section+"."+key)
+		}
+	}
+
+	assert.Equal(t, format(t, formatted, options), formatted, "Formatting should be idempotent")
+}
@@ -0,0 +1,429 @@
+package mini
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+Severity is how serious a Diagnostic is.
+*/
+type Severity int
+
+const (
+	//SeverityError is a mistake that changes how the file is read
+	SeverityError Severity = iota
+	//SeverityWarning is something that is probably a mistake
+	SeverityWarning
+	//SeverityNote is a question of style
+	SeverityNote
+)
+
+func (severity Severity) String() string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityNote:
+		return "note"
+	}
+	return fmt.Sprintf("Severity(%d)", int(severity))
+}
+
+/*
+Diagnostic is a problem found by Lint.
+*/
+type Diagnostic struct {
+	Pos      Position
+	Rule     string //the name of the rule that found the problem, or "syntax"
+	Severity Severity
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return d.Pos.String() + ": " + d.Severity.String() + ": " + d.Message + " (" + d.Rule + ")"
+}
+
+/*
+Rule is a check run by Lint. Check is given every line of the file, in order, and calls report for each problem.
+The built in rules can be copied to change their Severity.
+*/
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       func(tokens []Token, report func(pos Position, message string))
+}
+
+/*
+Lint reads an ini file from input and returns the problems found by rules, in the order of the lines they were found on.
+Without rules it uses DefaultRules. A line that can't be read is reported by the rule "syntax" and ends the file.
+*/
+func Lint(input io.Reader, rules ...Rule) []Diagnostic {
+
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+
+	scanner := NewScanner(input)
+	var tokens []Token
+	var diagnostics []Diagnostic
+
+	for {
+		token, err := scanner.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			diagnostics = append(diagnostics, Diagnostic{
+				Pos:      Position{Line: scanner.line},
+				Rule:     "syntax",
+				Severity: SeverityError,
+				Message:  strings.TrimPrefix(err.Error(), "mini: "),
+			})
+			break
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	for _, rule := range rules {
+		rule := rule
+
+		rule.Check(tokens, func(pos Position, message string) {
+			diagnostics = append(diagnostics, Diagnostic{Pos: pos, Rule: rule.Name, Severity: rule.Severity, Message: message})
+		})
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Pos.Line < diagnostics[j].Pos.Line
+	})
+
+	return diagnostics
+}
+
+/*
+DefaultRules returns the built in rules that don't need a schema.
+*/
+func DefaultRules() []Rule {
+	return []Rule{
+		RuleDuplicateKeys,
+		RuleGlobalAfterSection,
+		RuleMixedForms,
+		RuleEmptySection,
+		RuleTrailingSpace,
+		RuleInlineComment,
+		RuleInvalidUTF8,
+	}
+}
+
+/*
+OptionalRules returns the built in rules that are left out of DefaultRules because they are only guesses.
+*/
+func OptionalRules() []Rule {
+	return []Rule{
+		RuleUnindentedKey,
+	}
+}
+
+//Call f with each key and the normalized name of its section, "" for the global values.
+//Lint isn't given the options, so [prod : base] is taken to be prod extending base
+func eachKey(tokens []Token, f func(section string, token Token)) {
+	section := ""
+
+	for _, token := range tokens {
+		switch token.Kind {
+		case SectionStart:
+			section = sectionDocKey(token.Section, true)
+		case KeyValue, ArrayItem, MapItem:
+			f(section, token)
+		}
+	}
+}
+
+//Return the name of a section for a message
+func sectionLabel(section string) string {
+	if len(section) == 0 {
+		return "the global values"
+	}
+	return "[" + section + "]"
+}
+
+/*
+RuleDuplicateKeys reports keys, and names in map values, that are set more than once in a section.
+Only the last value is used.
+*/
+var RuleDuplicateKeys = Rule{
+	Name:        "duplicate-keys",
+	Description: "a key is set more than once in a section, only the last value is used",
+	Severity:    SeverityWarning,
+	Check: func(tokens []Token, report func(Position, string)) {
+		first := make(map[string]Position)
+
+		eachKey(tokens, func(section string, token Token) {
+			key := strings.ToLower(token.Key)
+			label := token.Key
+
+			switch token.Kind {
+			case ArrayItem:
+				return
+			case MapItem:
+				key += "[" + token.Name + "]"
+				label += "[" + token.Name + "]"
+			}
+
+			id := section + "\x00" + key
+
+			if pos, ok := first[id]; ok {
+				report(token.Pos, fmt.Sprintf("duplicate key %q in %s, first set at %s", label, sectionLabel(section), pos))
+				return
+			}
+
+			first[id] = token.Pos
+		})
+	},
+}
+
+/*
+RuleGlobalAfterSection reports empty section headers, [], written to return to the global values. Keys after one
+are read into a section named "", global values must come before the first section.
+*/
+var RuleGlobalAfterSection = Rule{
+	Name:        "global-after-section",
+	Description: "an empty section header doesn't return to the global values, global values must come before the first section",
+	Severity:    SeverityWarning,
+	Check: func(tokens []Token, report func(Position, string)) {
+		for _, token := range tokens {
+			if token.Kind == SectionStart && len(strings.TrimSpace(token.Section)) == 0 {
+				report(token.Pos, "an empty section header doesn't return to the global values, keys after it are in a section named \"\"")
+			}
+		}
+	},
+}
+
+/*
+RuleUnindentedKey reports unindented keys after a blank line in a section whose keys are indented, which may
+have been meant as global values. It only guesses from the layout of the file, so it is a note and isn't
+one of the DefaultRules.
+*/
+var RuleUnindentedKey = Rule{
+	Name:        "unindented-key",
+	Description: "a key isn't indented like the keys above it, it is still in their section",
+	Severity:    SeverityNote,
+	Check: func(tokens []Token, report func(Position, string)) {
+		indented, blank := false, false
+
+		for _, token := range tokens {
+			switch token.Kind {
+			case SectionStart:
+				indented, blank = false, false
+			case Blank:
+				blank = true
+			case KeyValue, ArrayItem, MapItem:
+				isIndented := len(token.Raw) > 0 && (token.Raw[0] == ' ' || token.Raw[0] == '\t')
+
+				if indented && blank && !isIndented {
+					report(token.Pos, fmt.Sprintf("key %q isn't indented like the keys above it, it is in the section above, not a global value", token.Key))
+				}
+
+				indented, blank = isIndented, false
+			}
+		}
+	},
+}
+
+/*
+RuleMixedForms reports keys written in more than one of the forms key=value, key[]=value and key[name]=value
+in a section. Only the last form is used.
+*/
+var RuleMixedForms = Rule{
+	Name:        "mixed-forms",
+	Description: "a key is written both as a single value and as an array or map, only the last form is used",
+	Severity:    SeverityWarning,
+	Check: func(tokens []Token, report func(Position, string)) {
+		type form struct {
+			kind TokenKind
+			pos  Position
+		}
+
+		first := make(map[string]form)
+
+		eachKey(tokens, func(section string, token Token) {
+			id := section + "\x00" + strings.ToLower(token.Key)
+			seen, ok := first[id]
+
+			if !ok {
+				first[id] = form{token.Kind, token.Pos}
+				return
+			}
+
+			if seen.kind != token.Kind {
+				report(token.Pos, fmt.Sprintf("key %q is written as %s here and as %s at %s", token.Key, writtenForm(token.Kind), writtenForm(seen.kind), seen.pos))
+				first[id] = form{token.Kind, token.Pos}
+			}
+		})
+	},
+}
+
+//Return how a kind of key is written, for messages
+func writtenForm(kind TokenKind) string {
+	switch kind {
+	case ArrayItem:
+		return "key[]=value"
+	case MapItem:
+		return "key[name]=value"
+	}
+	return "key=value"
+}
+
+/*
+RuleEmptySection reports section headers with no keys before the next header or the end of the file.
+*/
+var RuleEmptySection = Rule{
+	Name:        "empty-section",
+	Description: "a section header has no keys under it",
+	Severity:    SeverityNote,
+	Check: func(tokens []Token, report func(Position, string)) {
+		var header *Token
+
+		for i := range tokens {
+			switch tokens[i].Kind {
+			case SectionStart:
+				if header != nil {
+					report(header.Pos, fmt.Sprintf("section [%s] is empty", strings.TrimSpace(header.Section)))
+				}
+				header = &tokens[i]
+			case KeyValue, ArrayItem, MapItem:
+				header = nil
+			}
+		}
+
+		if header != nil {
+			report(header.Pos, fmt.Sprintf("section [%s] is empty", strings.TrimSpace(header.Section)))
+		}
+	},
+}
+
+//Return the value of a key as it was written, including any quotes
+func writtenValue(token Token) string {
+	return strings.TrimSpace(token.Text[strings.IndexByte(token.Text, '=')+1:])
+}
+
+/*
+RuleTrailingSpace reports unquoted values followed by white space, which is removed when the value is read.
+*/
+var RuleTrailingSpace = Rule{
+	Name:        "trailing-space",
+	Description: "white space after an unquoted value is removed, quote the value to keep it",
+	Severity:    SeverityWarning,
+	Check: func(tokens []Token, report func(Position, string)) {
+		eachKey(tokens, func(section string, token Token) {
+			value := writtenValue(token)
+
+			if len(value) == 0 || trimQuotes(value) != value {
+				return
+			}
+
+			if trimmed := strings.TrimRight(token.Raw, " \t"); len(trimmed) < len(token.Raw) {
+				report(token.Pos, fmt.Sprintf("the white space after the value of %q is removed, quote the value to keep it", token.Key))
+			}
+		})
+	},
+}
+
+/*
+RuleInlineComment reports unquoted values containing " #" or " ;", which are read as part of the value.
+Comments must be on a line of their own.
+*/
+var RuleInlineComment = Rule{
+	Name:        "inline-comment",
+	Description: "a value contains text that looks like a comment, comments must be on a line of their own",
+	Severity:    SeverityWarning,
+	Check: func(tokens []Token, report func(Position, string)) {
+		eachKey(tokens, func(section string, token Token) {
+			value := writtenValue(token)
+
+			if trimQuotes(value) != value {
+				return
+			}
+
+			for i := 1; i < len(value); i++ {
+				if (value[i] == '#' || value[i] == ';') && (value[i-1] == ' ' || value[i-1] == '\t') {
+					report(token.Pos, fmt.Sprintf("%q is part of the value of %q, not a comment", strings.TrimSpace(value[i-1:]), token.Key))
+					return
+				}
+			}
+		})
+	},
+}
+
+/*
+RuleInvalidUTF8 reports lines that aren't valid UTF-8.
+*/
+var RuleInvalidUTF8 = Rule{
+	Name:        "invalid-utf8",
+	Description: "a line contains bytes that aren't valid UTF-8",
+	Severity:    SeverityError,
+	Check: func(tokens []Token, report func(Position, string)) {
+		for _, token := range tokens {
+			for i := 0; i < len(token.Raw); {
+				r, size := utf8.DecodeRuneInString(token.Raw[i:])
+
+				if r == utf8.RuneError && size == 1 {
+					report(token.Pos, fmt.Sprintf("byte %d of the line, 0x%02x, isn't valid UTF-8", i+1, token.Raw[i]))
+					break
+				}
+
+				i += size
+			}
+		}
+	},
+}
+
+/*
+RuleUnknownKeys returns a rule that reports sections and keys that aren't in schema, a config holding every
+section and key that is allowed, such as one loaded from an example file. Sections are compared using schema's Options.
+With Options.Extends set, the extends key of a section names the section it extends and is never unknown.
+*/
+func RuleUnknownKeys(schema *Config) Rule {
+	return Rule{
+		Name:        "unknown-keys",
+		Description: "a section or key isn't in the schema",
+		Severity:    SeverityError,
+		Check: func(tokens []Token, report func(Position, string)) {
+			known := &(schema.configSection)
+			name := ""
+
+			for _, token := range tokens {
+				switch token.Kind {
+				case SectionStart:
+					name = sectionDocKey(strings.TrimSpace(token.Section), schema.Options.Extends)
+					known = schema.sections[schema.Options.sectionKey(name)]
+
+					if known == nil {
+						report(token.Pos, fmt.Sprintf("unknown section [%s]", name))
+					}
+				case KeyValue, ArrayItem, MapItem:
+					if known == nil {
+						continue
+					}
+
+					//the loader reads extends as a directive rather than storing it, so the schema can't hold it
+					if schema.Options.Extends && token.Kind == KeyValue && len(name) > 0 && strings.EqualFold(token.Key, extendsKey) {
+						continue
+					}
+
+					if _, ok := known.values[strings.ToLower(token.Key)]; !ok {
+						report(token.Pos, fmt.Sprintf("unknown key %q in %s", token.Key, sectionLabel(name)))
+					}
+				}
+			}
+		},
+	}
+}
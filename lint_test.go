@@ -0,0 +1,85 @@
+package mini
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+//Return the rule and line of each diagnostic
+func lintResults(ini string, rules ...Rule) []string {
+	var results []string
+
+	for _, d := range Lint(strings.NewReader(ini), rules...) {
+		results = append(results, d.Rule+":"+d.Pos.String())
+	}
+
+	return results
+}
+
+func TestLint(t *testing.T) {
+
+	ini := `a=1
+a=2
+[db]
+  host=x 
+  k[]=1
+  m[x]=1
+  m[x]=2
+
+port=5 # the port
+k=2
+[]
+[empty]
+[db]
+name="quoted # not a comment  "
+`
+	assert.Equal(t, lintResults(ini), []string{
+		"duplicate-keys:line 2",
+		"trailing-space:line 4",
+		"duplicate-keys:line 7",
+		"inline-comment:line 9",
+		"mixed-forms:line 10",
+		"global-after-section:line 11",
+		"empty-section:line 11",
+		"empty-section:line 12",
+	}, "Lint should find each problem")
+
+	assert.Nil(t, Lint(strings.NewReader("a=1\n[db]\nb=\"2 \"\n")), "Clean file should have no diagnostics")
+	assert.Equal(t, lintResults(ini, RuleUnindentedKey), []string{"unindented-key:line 9"}, "Unindented key after indented keys should be noted")
+}
+
+func TestLintDiagnostic(t *testing.T) {
+
+	diagnostics := Lint(strings.NewReader("a=1\n[db]\nk=1\nk[]=2\n"), RuleMixedForms)
+
+	assert.Equal(t, len(diagnostics), 1, "Only the given rule should run")
+	assert.Equal(t, diagnostics[0].Severity, SeverityWarning, "Diagnostic should have the rule's severity")
+	assert.Equal(t, diagnostics[0].String(), `line 4: warning: key "k" is written as key[]=value here and as key=value at line 3 (mixed-forms)`, "Diagnostic should describe the problem")
+
+	strict := RuleMixedForms
+	strict.Severity = SeverityError
+	assert.Equal(t, Lint(strings.NewReader("k=1\nk[]=2\n"), strict)[0].Severity, SeverityError, "Copied rule should use its own severity")
+}
+
+func TestLintErrors(t *testing.T) {
+
+	assert.Equal(t, lintResults("a=1\n[db\nb=2\n"), []string{"syntax:line 2"}, "Syntax error should be reported at its line")
+	assert.Equal(t, lintResults("a=1\nb=\xff\n", RuleInvalidUTF8), []string{"invalid-utf8:line 2"}, "Invalid UTF-8 should be reported")
+
+	schema, err := LoadConfigurationFromReader(strings.NewReader("name=\n[db]\nhost=\n"))
+	assert.Nil(t, err, "Schema should load without error.")
+
+	ini := "name=x\nother=y\n[db]\nhost=a\nport=1\n[cache]\nsize=1\n"
+	assert.Equal(t, lintResults(ini, RuleUnknownKeys(schema)), []string{
+		"unknown-keys:line 2",
+		"unknown-keys:line 5",
+		"unknown-keys:line 6",
+	}, "Unknown sections and keys should be reported")
+
+	schema = &Config{Options: Options{Extends: true}}
+	assert.Nil(t, schema.InitializeFromReader(strings.NewReader("[base]\nhost=\n[prod]\nextends=base\n")), "Schema should load without error.")
+
+	ini = "extends=base\n[base]\nhost=a\n[prod]\nextends = base\n"
+	assert.Equal(t, lintResults(ini, RuleUnknownKeys(schema)), []string{"unknown-keys:line 1"}, "Extends should only be known in a section")
+}
@@ -0,0 +1,122 @@
+package mini
+
+import (
+	"strings"
+)
+
+//Remove the quotes around a value that is a single quoted string, other values are returned unchanged
+func trimQuotes(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+
+	quote := value[0]
+
+	if (quote != '"' && quote != '\'') || value[len(value)-1] != quote {
+		return value
+	}
+
+	inner := value[1 : len(value)-1]
+
+	for i := 0; i < len(inner); i++ {
+		switch inner[i] {
+		case '\\':
+			i++ //skip the escaped character
+		case quote:
+			return value //more than one quoted string, as in "a", "b"
+		}
+	}
+
+	return inner
+}
+
+/*
+Split value at each sep that isn't inside quotes, the entries are trimmed and have their quotes removed.
+Only a quote at the start of an entry begins a quoted string, so O'Brien is read as it is. An empty value has no entries.
+*/
+func splitList(value string, sep string) []string {
+	if len(strings.TrimSpace(value)) == 0 {
+		return make([]string, 0)
+	}
+
+	if len(sep) == 0 {
+		return []string{trimQuotes(strings.TrimSpace(value))}
+	}
+
+	list := make([]string, 0, strings.Count(value, sep)+1)
+	var quote byte
+	start := 0
+
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case quote != 0 && c == '\\':
+			i++ //skip the escaped character
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && len(strings.TrimSpace(value[start:i])) == 0:
+			quote = c
+		case strings.HasPrefix(value[i:], sep):
+			list = append(list, trimQuotes(strings.TrimSpace(value[start:i])))
+			i += len(sep) - 1
+			start = i + 1
+		}
+	}
+
+	return append(list, trimQuotes(strings.TrimSpace(value[start:])))
+}
+
+//Return the entries in a value written as [a, b, c]
+func parseInlineList(value string) ([]string, bool) {
+	if len(value) < 2 || value[0] != '[' || value[len(value)-1] != ']' {
+		return nil, false
+	}
+
+	return splitList(value[1:len(value)-1], ","), true
+}
+
+//Return array values, splitting a single value that isn't written as [a, b, c] at each sep
+func getSplitArray(section *configSection, key string, sep string) []*value {
+	val := get(section, key)
+
+	if val == nil || val.entries() != nil {
+		return getArray(section, key)
+	}
+
+	list := splitList(val.raw, sep)
+	retVal := make([]*value, len(list))
+
+	for i, entry := range list {
+		retVal[i] = newValue(entry, int(val.line), val.src)
+	}
+
+	return retVal
+}
+
+/*
+StringsSep looks for an array of strings under the provided key. A single value is split at each sep
+that isn't inside quotes, so hosts = a,"b,c" with a sep of "," is read as a and b,c. Arrays written
+as key[]=value, or [a, b, c] with Options.InlineLists, are returned as they are.
+If no matches are found nil is returned.
+*/
+func (config *Config) StringsSep(key string, sep string) []string {
+	return toStrings(getSplitArray(&(config.configSection), key, sep))
+}
+
+/*
+StringsSepFromSection looks for an array of strings in the provided section and under the provided key,
+splitting a single value at each sep that isn't inside quotes, see StringsSep.
+If no matches are found nil is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) StringsSepFromSection(sectionName string, key string, sep string) []string {
+	section := config.sectionForName(sectionName)
+
+	if section != nil {
+		return toStrings(getSplitArray(section, key, sep))
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package mini
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+const listIni = `hosts = [a, b, c]
+quoted = ["a,b", 'c', " d "]
+empty = []
+ints = [1, 2, 0x10]
+floats = [1.5, 2.5]
+csv = a,"b,c",d
+semi = a; b ;c
+single = "hello world"
+pair = "a", "b"
+escaped = "say \"hi\""
+pattern = [a-z]
+addr = "[::1]"
+literal = '[a, b]'
+names = O'Brien, Smith
+list[]=x
+list[]=y
+
+[section]
+durations = 1s|1m
+ports = [80, 443]`
+
+//Load listIni with inline lists turned on
+func loadListIni() (*Config, error) {
+	config := &Config{Options: Options{InlineLists: true}}
+	return config, config.InitializeFromReader(strings.NewReader(listIni))
+}
+
+func TestInlineArrays(t *testing.T) {
+
+	config, err := loadListIni()
+
+	assert.Nil(t, err, "List configuration should load without error.")
+
+	assert.Equal(t, config.Strings("hosts"), []string{"a", "b", "c"}, "Read value of hosts wrong")
+	assert.Equal(t, config.String("hosts", ""), "[a, b, c]", "Inline arrays are still single values")
+	assert.Equal(t, config.Strings("quoted"), []string{"a,b", "c", " d "}, "Quoted entries should keep commas and spaces")
+	assert.Equal(t, config.Strings("empty"), []string{}, "Read value of empty wrong")
+	assert.Equal(t, config.Integers("ints"), []int64{1, 2, 16}, "Read value of ints wrong")
+	assert.Equal(t, config.Floats("floats"), []float64{1.5, 2.5}, "Read value of floats wrong")
+	assert.Equal(t, config.IntegersFromSection("section", "ports"), []int64{80, 443}, "Read value of ports wrong")
+	assert.Equal(t, config.Strings("pattern"), []string{"a-z"}, "Brackets are always an inline array")
+	assert.Equal(t, config.Strings("addr"), []string{"[::1]"}, "Brackets in quotes are text")
+	assert.Equal(t, config.String("literal", ""), "[a, b]", "Quoted brackets should keep their text")
+	assert.Equal(t, config.Strings("literal"), []string{"[a, b]"}, "Brackets in quotes are text")
+	assert.Equal(t, config.Strings("semi"), []string{"a; b ;c"}, "Delimited values are only split when asked")
+}
+
+func TestInlineListsOff(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader("addr=[::1]\nhosts=[a, b]\nlist[]=x"))
+
+	assert.Nil(t, err, "List configuration should load without error.")
+
+	assert.Equal(t, config.Strings("addr"), []string{"[::1]"}, "Brackets are text without Options.InlineLists")
+	assert.Equal(t, config.String("hosts", ""), "[a, b]", "Read value of hosts wrong")
+	assert.Equal(t, config.Strings("hosts"), []string{"[a, b]"}, "Brackets are text without Options.InlineLists")
+	assert.Equal(t, config.Strings("list"), []string{"x"}, "Repeated keys are still arrays")
+}
+
+func TestStringsSep(t *testing.T) {
+
+	config, err := loadListIni()
+
+	assert.Nil(t, err, "List configuration should load without error.")
+
+	assert.Equal(t, config.StringsSep("csv", ","), []string{"a", "b,c", "d"}, "Read value of csv wrong")
+	assert.Equal(t, config.StringsSep("semi", ";"), []string{"a", "b", "c"}, "Read value of semi wrong")
+	assert.Equal(t, config.StringsSep("names", ","), []string{"O'Brien", "Smith"}, "An apostrophe inside an entry isn't a quote")
+	assert.Equal(t, config.StringsSep("hosts", ";"), []string{"a", "b", "c"}, "Inline arrays aren't split again")
+	assert.Equal(t, config.StringsSep("list", ","), []string{"x", "y"}, "Arrays aren't split")
+	assert.Equal(t, config.StringsSep("single", ""), []string{"hello world"}, "An empty sep doesn't split")
+	assert.Equal(t, config.StringsSepFromSection("section", "durations", "|"), []string{"1s", "1m"}, "Read value of durations wrong")
+	assert.Nil(t, config.StringsSep("missing", ","), "Missing key should be nil")
+	assert.Nil(t, config.StringsSepFromSection("missing", "csv", ","), "Missing section should be nil")
+}
+
+func TestQuotes(t *testing.T) {
+
+	config, err := loadListIni()
+
+	assert.Nil(t, err, "List configuration should load without error.")
+
+	assert.Equal(t, config.String("single", ""), "hello world", "Quotes should be removed")
+	assert.Equal(t, config.String("escaped", ""), `say "hi"`, "Escaped quotes should be kept")
+	assert.Equal(t, config.StringsSep("pair", ","), []string{"a", "b"}, "Only quotes around the whole value are removed")
+
+	config, err = LoadConfigurationFromReader(strings.NewReader(`open='abc
+close=abc'
+mixed="abc'
+pair='abc'`))
+
+	assert.Nil(t, err, "Quoted configuration should load without error.")
+
+	assert.Equal(t, config.String("open", ""), `'abc`, "A quote at one end should be kept")
+	assert.Equal(t, config.String("close", ""), `abc'`, "A quote at one end should be kept")
+
+	mixed, _ := config.Lookup("", "mixed")
+	assert.Equal(t, mixed.Raw(), `"abc'`, "Different quotes should be kept")
+	assert.Equal(t, config.String("pair", ""), "abc", "Matching quotes should be removed")
+}
+
+type listStruct struct {
+	Csv       []string        `sep:","`
+	Durations []time.Duration `sep:"|"`
+	Ports     []int64         `sep:","`
+	Hosts     []string
+}
+
+func TestLoadStructLists(t *testing.T) {
+
+	config, err := loadListIni()
+
+	assert.Nil(t, err, "List configuration should load without error.")
+
+	var data listStruct
+
+	assert.True(t, config.DataFromSection("section", &data), "load should succeed")
+	assert.Equal(t, data.Durations, []time.Duration{time.Second, time.Minute}, "Read value of durations wrong")
+	assert.Equal(t, data.Ports, []int64{80, 443}, "Read value of ports wrong")
+
+	assert.True(t, config.DataFromSection("", &data), "load should succeed")
+	assert.Equal(t, data.Csv, []string{"a", "b,c", "d"}, "Read value of csv wrong")
+	assert.Equal(t, data.Hosts, []string{"a", "b", "c"}, "Read value of hosts wrong")
+}
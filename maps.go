@@ -0,0 +1,129 @@
+package mini
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+//Return map values
+func getMap(section *configSection, key string) *value {
+	val, ok := section.lookup(key)
+
+	if ok && val.kind() == MapValue {
+		return val
+	}
+
+	return nil
+}
+
+func getStringMap(section *configSection, key string) map[string]string {
+
+	val := getMap(section, key)
+
+	if val != nil {
+		retVal := make(map[string]string, len(val.mapEntries()))
+
+		for i, entry := range val.mapEntries() {
+			c := entry.resolve().converted()
+
+			if !c.textOK {
+				return nil
+			}
+			retVal[val.more.names[i]] = c.text
+		}
+		return retVal
+	}
+
+	return nil
+}
+
+//Convert a decoded string to a bool, string, int64, float64 or time.Duration of type t
+func convertString(str string, t reflect.Type, parseBool func(string) (bool, error)) (reflect.Value, error) {
+	var val interface{}
+	var err error
+
+	switch {
+	case t == durationType:
+		val, err = time.ParseDuration(str)
+	case t.Kind() == reflect.String:
+		val = str
+	case t.Kind() == reflect.Bool:
+		val, err = parseBool(str)
+	case t.Kind() == reflect.Int64:
+		val, err = strconv.ParseInt(str, 0, 64)
+	case t.Kind() == reflect.Float64:
+		val, err = strconv.ParseFloat(str, 64)
+	default:
+		return reflect.Value{}, fmt.Errorf("mini: unsupported type %s", t)
+	}
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return reflect.ValueOf(val).Convert(t), nil
+}
+
+//Build a map of type t, with string keys, from a map value
+func decodeMap(val map[string]string, t reflect.Type, parseBool func(string) (bool, error)) (reflect.Value, error) {
+	if t.Key().Kind() != reflect.String {
+		return reflect.Value{}, fmt.Errorf("mini: unsupported type %s", t)
+	}
+
+	retVal := reflect.MakeMapWithSize(t, len(val))
+
+	for k, v := range val {
+		elem, err := convertString(v, t.Elem(), parseBool)
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		retVal.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), elem)
+	}
+
+	return retVal, nil
+}
+
+/*
+Map looks for a map of strings under the provided key, written as key[name]=value.
+If no matches are found nil is returned.
+*/
+func (config *Config) Map(key string) map[string]string {
+	return getStringMap(&(config.configSection), key)
+}
+
+/*
+MapFromSection looks for a map of strings, written as key[name]=value, in the provided section and under the provided key.
+If no matches are found nil is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) MapFromSection(sectionName string, key string) map[string]string {
+	section := config.sectionForName(sectionName)
+
+	if section != nil {
+		return getStringMap(section, key)
+	}
+
+	return nil
+}
+
+/*
+MapKeysInOrder returns the names in the map stored under the provided key, in the order they first appeared in the file.
+Unlike keys, the names in a map are case sensitive.
+If no matches are found nil is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) MapKeysInOrder(sectionName string, key string) []string {
+	val := getMap(config.sectionForName(sectionName), key)
+
+	if val != nil {
+		return append([]string(nil), val.names()...)
+	}
+
+	return nil
+}
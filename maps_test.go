@@ -0,0 +1,77 @@
+package mini
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+const mapIni = `headers[Content-Type]=text/html
+headers[X-Frame-Options]=DENY
+Headers[Cache-Control]="no-cache"
+headers[Content-Type]=text/plain
+single=one
+list[]=two
+
+[routes]
+timeouts[/api]=5s
+timeouts[/static]=1m
+weights[a]=1.5
+weights[b]=2
+enabled[a]=true
+enabled[b]=false
+escaped[tab]=\t`
+
+func TestMap(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(mapIni))
+
+	assert.Nil(t, err, "Map configuration should load without error.")
+
+	headers := config.Map("headers")
+	assert.Equal(t, headers, map[string]string{
+		"Content-Type":    "text/plain",
+		"X-Frame-Options": "DENY",
+		"Cache-Control":   "no-cache",
+	}, "Read value of headers wrong")
+
+	assert.Equal(t, config.MapKeysInOrder("", "HEADERS"), []string{"Content-Type", "X-Frame-Options", "Cache-Control"}, "Map names should be in file order")
+	assert.Equal(t, config.MapFromSection("routes", "escaped")["tab"], "\t", "Map values should be unescaped")
+
+	assert.Nil(t, config.Map("single"), "Single values aren't maps")
+	assert.Nil(t, config.Map("list"), "Arrays aren't maps")
+	assert.Nil(t, config.Map("missing"), "Missing maps should be nil")
+	assert.Nil(t, config.MapFromSection("missing", "headers"), "Missing section should be nil")
+	assert.Nil(t, config.MapKeysInOrder("", "missing"), "Missing maps should have no names")
+	assert.Equal(t, config.String("headers", "def"), "def", "Maps aren't single values")
+	assert.Nil(t, config.Strings("headers"), "Maps aren't arrays")
+
+	assert.Equal(t, config.Keys(), []string{"headers", "list", "single"}, "Map keys should be listed once")
+}
+
+type mapStruct struct {
+	Timeouts map[string]time.Duration
+	Weights  map[string]float64
+	Enabled  map[string]bool
+	Escaped  map[string]string
+	Missing  map[string]string
+	Bad      map[int]string
+}
+
+func TestLoadStructMaps(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(mapIni + "\nbad[1]=one"))
+
+	assert.Nil(t, err, "Map configuration should load without error.")
+
+	var data mapStruct
+
+	assert.True(t, config.DataFromSection("routes", &data), "load should succeed")
+	assert.Equal(t, data.Timeouts, map[string]time.Duration{"/api": 5 * time.Second, "/static": time.Minute}, "Read value of timeouts wrong")
+	assert.Equal(t, data.Weights, map[string]float64{"a": 1.5, "b": 2}, "Read value of weights wrong")
+	assert.Equal(t, data.Enabled, map[string]bool{"a": true, "b": false}, "Read value of enabled wrong")
+	assert.Equal(t, data.Escaped, map[string]string{"tab": "\t"}, "Read value of escaped wrong")
+	assert.Nil(t, data.Missing, "Missing map should be nil")
+	assert.Nil(t, data.Bad, "Maps without string keys aren't supported")
+}
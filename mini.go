@@ -2,19 +2,135 @@ package mini
 
 import (
 	"bufio"
-	"errors"
-	"fmt"
 	"io"
 	"os"
 	"reflect"
 	"sort"
-	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type configSection struct {
 	name   string
-	values map[string]interface{}
+	values map[string]*value //values by lower case key
+	order  []string          //keys in the order they first appeared, as they were first spelled
+	parent *configSection    //searched for missing keys when Options.InheritValues is set
+
+	extends     *configSection //searched for missing keys before the parent when Options.Extends is set
+	extendsName string
+
+	occurrences int                 //the number of times the section appears in the file
+	pos         Position            //where the section first appears
+	overridden  map[string][]*value //values replaced by later assignments, by lower case key
+}
+
+//Create a section with room for size keys
+func newConfigSection(name string, size int) *configSection {
+	section := &configSection{name: name, values: make(map[string]*value, size)}
+
+	if size > 4 { //set makes room for 4 keys
+		section.order = make([]string, 0, size)
+	}
+	return section
+}
+
+func (section *configSection) init(name string) {
+	section.name = name
+	section.values = make(map[string]*value)
+	section.order = nil
+	section.parent = nil
+	section.extends = nil
+	section.extendsName = ""
+	section.occurrences = 0
+	section.overridden = nil
+}
+
+//Find a value by key, searching the extended and parent sections if the key is missing
+func (section *configSection) lookup(key string) (*value, bool) {
+	if len(key) == 0 {
+		return nil, false
+	}
+
+	var buf [64]byte
+	return section.find(appendLower(buf[:0], key))
+}
+
+//The key is a byte slice so that lookups with short keys don't allocate
+func (section *configSection) find(key []byte) (*value, bool) {
+	val, found := section.locate(key)
+	return val, found != nil
+}
+
+//Find a value by key and return the section it was found in, which is nil if the key is missing.
+//Each section is searched along with the sections it extends before moving on to its parent.
+func (section *configSection) locate(key []byte) (*value, *configSection) {
+	for ; section != nil; section = section.parent {
+		for extended := section; extended != nil; extended = extended.extends {
+			if val, ok := extended.values[string(key)]; ok {
+				return val, extended
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+//Set a value, replacing existing, the key is stored in lower case but the first spelling seen is remembered
+func (section *configSection) set(name string, lowerKey string, existing *value, val *value) {
+	if existing != nil {
+		section.values[lowerKey] = val
+		section.override(lowerKey, existing)
+		return
+	}
+
+	if section.order == nil {
+		section.order = make([]string, 0, 4) //room for a small section
+	}
+
+	section.order = append(section.order, name)
+	section.values[lowerKey] = val
+}
+
+//Remember a value that was replaced by a later assignment, for Origin
+func (section *configSection) override(key string, old *value) {
+	if section.overridden == nil {
+		section.overridden = make(map[string][]*value)
+	}
+
+	section.overridden[key] = append(section.overridden[key], old)
+}
+
+//Return the keys, as they were spelled in the file, sorted by their lower case form
+func (section *configSection) sortedKeys() []string {
+	keys := section.orderedKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.ToLower(keys[i]) < strings.ToLower(keys[j])
+	})
+	return keys
+}
+
+//Return the keys, as they were spelled in the file, in the order they first appeared
+func (section *configSection) orderedKeys() []string {
+	return append([]string(nil), section.order...)
+}
+
+//Return the spelling used in the file for a lower case key
+func (section *configSection) keyName(key string) string {
+	var buf [64]byte
+
+	for _, name := range section.order {
+		if string(appendLower(buf[:0], name)) == key {
+			return name
+		}
+	}
+	return key
+}
+
+//Return the value stored in the section itself under key, in any case
+func (section *configSection) own(key string) *value {
+	var buf [64]byte
+	return section.values[string(appendLower(buf[:0], key))]
 }
 
 /*
@@ -22,7 +138,13 @@ Config holds the contents of an ini file organized into sections.
 */
 type Config struct {
 	configSection
-	sections map[string]*configSection
+	sections     map[string]*configSection
+	sectionOrder []*configSection
+	warnings     []Warning
+	src          *source //shared by the values that were loaded
+
+	//Options controls how the config is parsed and searched, it should be set before the config is initialized
+	Options Options
 }
 
 /*
@@ -67,7 +189,7 @@ func (config *Config) InitializeFromPath(path string) error {
 
 	defer f.Close()
 
-	return config.InitializeFromReader(bufio.NewReader(f))
+	return config.initialize(bufio.NewReader(f), path)
 }
 
 /*
@@ -75,214 +197,287 @@ InitializeFromReader takes a reader and scans it for an ini configuration.
 The caller should close the reader.
 */
 func (config *Config) InitializeFromReader(input io.Reader) error {
+	return config.initialize(input, "")
+}
 
-	var currentSection *configSection
+//Scan input for an ini configuration, path is used in the position of each value
+func (config *Config) initialize(input io.Reader, path string) error {
 
-	scanner := bufio.NewScanner(input)
-	config.values = make(map[string]interface{})
+	scanner := NewScanner(input)
+	scanner.file = path
+	scanner.invalidUTF8 = config.Options.InvalidUTF8
+	config.configSection.init(config.name)
 	config.sections = make(map[string]*configSection)
+	config.sectionOrder = nil
+	config.warnings = nil
+	config.src = &source{file: path, options: &config.Options}
+
+	currentSection := &(config.configSection)
+	currentSection.occurrences = 1
+	currentName := "" //the name of the current section, "" for the global values
+	skipping := false //the current occurrence of the section is ignored, by DuplicatesFirstWins
+
+	var keyBuf [64]byte
+	var values valueBlock
+	lowerKeys := make(map[string]string) //one copy of each lower case key, shared by the sections that use it
+
+	for scanner.scan() {
+		switch scanner.kind {
+		case Blank, Comment:
+			continue
+		case SectionStart:
+			var extends string
+			currentName, extends = splitSectionName(string(scanner.value), config.Options.Extends)
+			currentSection = config.addSection(currentName)
 
-	for scanner.Scan() {
-		curLine := scanner.Text()
+			var err error
+			skipping, err = config.duplicateSection(currentSection, Position{File: path, Line: scanner.line})
+
+			if err != nil {
+				return err
+			}
 
-		curLine = strings.TrimSpace(curLine)
+			currentSection.occurrences++
 
-		if len(curLine) == 0 {
-			continue // ignore empty lines
+			if skipping {
+				continue
+			}
+
+			if len(extends) > 0 {
+				currentSection.extendsName = extends
+			}
+
+			continue
 		}
 
-		if strings.HasPrefix(curLine, ";") || strings.HasPrefix(curLine, "#") {
-			continue // comment
+		if skipping {
+			continue
 		}
 
-		if strings.HasPrefix(curLine, "[") {
+		lowerKey := appendLower(keyBuf[:0], scanner.key)
+		existing := currentSection.values[string(lowerKey)]
 
-			if !strings.HasSuffix(curLine, "]") {
-				return errors.New("mini: section names must be surrounded by [ and ], as in [section]")
-			}
+		//the key, name and value are cut from one string, so each line is a single allocation. Items added
+		//to an array or map that already exists only keep the line from their name or value on.
+		line, key := scanner.text, ""
+		extending := scanner.kind != KeyValue && existing != nil && existing.kind() == kindOfToken(scanner.kind)
 
-			sectionName := curLine[1 : len(curLine)-1]
+		if extending && scanner.kind == MapItem {
+			line = scanner.from(scanner.name)
+		} else if extending {
+			line = scanner.from(scanner.value)
+		}
 
-			if sect, ok := config.sections[sectionName]; !ok { //reuse sections
-				currentSection = new(configSection)
-				currentSection.name = sectionName
-				currentSection.values = make(map[string]interface{})
-				config.sections[currentSection.name] = currentSection
-			} else {
-				currentSection = sect
-			}
+		text := string(line)
+		written := scanner.cut(text, scanner.value)
+		value := trimQuotes(written)
 
+		if !extending {
+			key = scanner.cut(text, scanner.key)
+		} else if config.Options.Decrypter != nil || len(config.Options.SecretKeys) > 0 {
+			key = string(scanner.key)
+		}
+
+		if config.Options.Extends && scanner.kind == KeyValue && currentSection != &(config.configSection) && strings.EqualFold(key, extendsKey) {
+			currentSection.extendsName = parseSectionName(value)
 			continue
 		}
 
-		index := strings.Index(curLine, "=")
+		pos := Position{File: path, Line: scanner.line}
+
+		if existing != nil {
+			skip, err := config.duplicateKey(currentSection, currentName, lowerKey, scanner.kind, scanner.name, pos)
+
+			if err != nil {
+				return err
+			}
+
+			if skip {
+				continue
+			}
+		}
+
+		val := values.next().initScalar(value, scanner.line, config.src)
 
-		if index <= 0 {
-			return errors.New("mini: configuration format requires an equals between the key and value")
+		if config.Options.InlineLists && len(value) == len(written) {
+			val.parseList() //a value in quotes, like "[::1]", is never a list
 		}
 
-		key := strings.ToLower(strings.TrimSpace(curLine[0:index]))
-		isArray := strings.HasSuffix(key, "[]")
+		if config.Options.Decrypter != nil && isEncrypted(value) {
+			decrypted, err := config.src.decrypt(value, currentName, key, scanner.line)
 
-		if isArray {
-			key = key[0 : len(key)-2]
+			if err != nil {
+				return err
+			}
+
+			val = decrypted
 		}
 
-		value := strings.TrimSpace(curLine[index+1:])
-		value = strings.Trim(value, "\"'") //clear quotes
+		val.occurrence = int32(currentSection.occurrences)
+		secret := len(config.Options.SecretKeys) > 0 && config.Options.isSecret(currentName, key)
 
-		valueMap := config.values
+		if secret {
+			val.markSecret()
+		}
 
-		if currentSection != nil {
-			valueMap = currentSection.values
+		if scanner.kind == ArrayItem {
+			val = existing.appendEntry(val)
+		} else if scanner.kind == MapItem {
+			name := scanner.cut(text, scanner.name)
+
+			if old, ok := existing.mapEntry(name); ok {
+				currentSection.override(string(lowerKey), old)
+			}
+
+			val = existing.setEntry(name, val)
+		}
+
+		if secret {
+			val.extra().secret = true
 		}
 
-		if isArray {
-			arr := valueMap[key]
+		if val != existing { //arrays and maps are updated in place
+			lower, ok := lowerKeys[string(lowerKey)]
 
-			if arr == nil {
-				arr = make([]interface{}, 0)
-				valueMap[key] = arr
+			if !ok {
+				lower = string(lowerKey)
+				lowerKeys[lower] = lower
 			}
 
-			valueMap[key] = append(arr.([]interface{}), value)
-		} else {
-			valueMap[key] = value
+			currentSection.set(key, lower, existing, val)
 		}
 	}
 
-	return scanner.Err()
+	if scanner.err != io.EOF {
+		return scanner.err
+	}
+
+	return config.linkSections()
+}
+
+//Return the section named sectionName, adding it if it doesn't exist, split sections reuse the first section
+func (config *Config) addSection(sectionName string) *configSection {
+	sectionKey := config.Options.sectionKey(sectionName)
+
+	if section, ok := config.sections[sectionKey]; ok {
+		return section
+	}
+
+	//sections in a file tend to be alike, so make room for as many keys as the last one has
+	size := 0
+	if n := len(config.sectionOrder); n > 0 {
+		size = len(config.sectionOrder[n-1].order)
+	}
+
+	section := newConfigSection(sectionName, size)
+	config.sections[sectionKey] = section
+
+	if config.sectionOrder == nil {
+		config.sectionOrder = make([]*configSection, 0, 4) //room for a few sections
+	}
+
+	config.sectionOrder = append(config.sectionOrder, section)
+	return section
 }
 
 /*
 SetName sets the config's name, which allows it to be returned in SectionNames, or in get functions that take a name.
+The name is compared using the same rules as section names.
 */
 func (config *Config) SetName(name string) {
 	config.name = name
 }
 
 //Return non-array values
-func get(values map[string]interface{}, key string) interface{} {
-	if len(key) == 0 || values == nil {
-		return nil
-	}
+func get(section *configSection, key string) *value {
+	val, ok := section.lookup(key)
 
-	key = strings.ToLower(key)
-	val, ok := values[key]
-
-	if ok {
-		switch val.(type) {
-		case []interface{}:
-			return nil
-		default:
-			return val
-		}
+	if ok && val.kind() == ScalarValue {
+		return val.resolve()
 	}
 
 	return nil
 }
 
 //Return array values
-func getArray(values map[string]interface{}, key string) []interface{} {
-	if len(key) == 0 || values == nil {
-		return nil
-	}
-
-	key = strings.ToLower(key)
-	val, ok := values[key]
+func getArray(section *configSection, key string) []*value {
+	val, ok := section.lookup(key)
 
 	if ok {
-		switch v := val.(type) {
-		case []interface{}:
-			return v
-		default:
-			retVal := make([]interface{}, 1)
-			retVal[0] = val
-			return retVal
-		}
+		return resolveAll(val.list())
 	}
 
 	return nil
 }
 
-func getString(values map[string]interface{}, key string, def string) string {
+func getString(section *configSection, key string, def string) string {
 
-	val := get(values, key)
+	val := get(section, key)
 
 	if val != nil {
-		str, err := strconv.Unquote(fmt.Sprintf("\"%v\"", val))
-
-		if err == nil {
-			return str
+		if c := val.converted(); c.textOK {
+			return c.text
 		}
-
-		return def
 	}
 
 	return def
 }
 
-func getBoolean(values map[string]interface{}, key string, def bool) bool {
+func getBoolean(section *configSection, key string, def bool) bool {
 
-	val := get(values, key)
+	val := get(section, key)
 
 	if val != nil {
-		retVal, err := strconv.ParseBool(fmt.Sprint(val))
-
-		if err != nil {
-			return def
+		if c := val.converted(); c.booleanOK {
+			return c.boolean
 		}
-		return retVal
 	}
 
 	return def
 }
 
-func getInteger(values map[string]interface{}, key string, def int64) int64 {
+func getInteger(section *configSection, key string, def int64) int64 {
 
-	val := get(values, key)
+	val := get(section, key)
 
 	if val != nil {
-		retVal, err := strconv.ParseInt(fmt.Sprint(val), 0, 64)
-
-		if err != nil {
-			return def
+		if c := val.converted(); c.integerOK {
+			return c.integer
 		}
-		return retVal
 	}
 
 	return def
 }
 
-func getFloat(values map[string]interface{}, key string, def float64) float64 {
+func getFloat(section *configSection, key string, def float64) float64 {
 
-	val := get(values, key)
+	val := get(section, key)
 
 	if val != nil {
-		retVal, err := strconv.ParseFloat(fmt.Sprint(val), 64)
-
-		if err != nil {
-			return def
+		if c := val.converted(); c.floatOK {
+			return c.float
 		}
-		return retVal
 	}
 
 	return def
 }
 
-func getStrings(values map[string]interface{}, key string) []string {
+func getStrings(section *configSection, key string) []string {
+	return toStrings(getArray(section, key))
+}
 
-	val := getArray(values, key)
+func toStrings(val []*value) []string {
 
 	if val != nil {
 		retVal := make([]string, len(val))
 
-		var err error
 		for i, v := range val {
-			retVal[i], err = strconv.Unquote(fmt.Sprintf("\"%v\"", v))
-			if err != nil {
+			c := v.converted()
+
+			if !c.textOK {
 				return nil
 			}
+			retVal[i] = c.text
 		}
 		return retVal
 	}
@@ -290,19 +485,22 @@ func getStrings(values map[string]interface{}, key string) []string {
 	return nil
 }
 
-func getIntegers(values map[string]interface{}, key string) []int64 {
+func getIntegers(section *configSection, key string) []int64 {
+	return toIntegers(getArray(section, key))
+}
 
-	val := getArray(values, key)
+func toIntegers(val []*value) []int64 {
 
 	if val != nil {
 		retVal := make([]int64, len(val))
 
-		var err error
 		for i, v := range val {
-			retVal[i], err = strconv.ParseInt(fmt.Sprint(v), 0, 64)
-			if err != nil {
+			c := v.converted()
+
+			if !c.integerOK {
 				return nil
 			}
+			retVal[i] = c.integer
 		}
 		return retVal
 	}
@@ -310,19 +508,22 @@ func getIntegers(values map[string]interface{}, key string) []int64 {
 	return nil
 }
 
-func getFloats(values map[string]interface{}, key string) []float64 {
+func getFloats(section *configSection, key string) []float64 {
+	return toFloats(getArray(section, key))
+}
 
-	val := getArray(values, key)
+func toFloats(val []*value) []float64 {
 
 	if val != nil {
 		retVal := make([]float64, len(val))
 
-		var err error
 		for i, v := range val {
-			retVal[i], err = strconv.ParseFloat(fmt.Sprint(v), 64)
-			if err != nil {
+			c := v.converted()
+
+			if !c.floatOK {
 				return nil
 			}
+			retVal[i] = c.float
 		}
 		return retVal
 	}
@@ -334,28 +535,28 @@ func getFloats(values map[string]interface{}, key string) []float64 {
 String looks for the specified key and returns it as a string. If not found the default value def is returned.
 */
 func (config *Config) String(key string, def string) string {
-	return getString(config.values, key, def)
+	return getString(&(config.configSection), key, def)
 }
 
 /*
 Boolean looks for the specified key and returns it as a bool. If not found the default value def is returned.
 */
 func (config *Config) Boolean(key string, def bool) bool {
-	return getBoolean(config.values, key, def)
+	return getBoolean(&(config.configSection), key, def)
 }
 
 /*
 Integer looks for the specified key and returns it as an int. If not found the default value def is returned.
 */
 func (config *Config) Integer(key string, def int64) int64 {
-	return getInteger(config.values, key, def)
+	return getInteger(&(config.configSection), key, def)
 }
 
 /*
 Float looks for the specified key and returns it as a float. If not found the default value def is returned.
 */
 func (config *Config) Float(key string, def float64) float64 {
-	return getFloat(config.values, key, def)
+	return getFloat(&(config.configSection), key, def)
 }
 
 /*
@@ -363,7 +564,7 @@ Strings looks for an array of strings under the provided key.
 If no matches are found nil is returned. If only one matches an array of 1 is returned.
 */
 func (config *Config) Strings(key string) []string {
-	return getStrings(config.values, key)
+	return getStrings(&(config.configSection), key)
 }
 
 /*
@@ -371,7 +572,7 @@ Integers looks for an array of ints under the provided key.
 If no matches are found nil is returned.
 */
 func (config *Config) Integers(key string) []int64 {
-	return getIntegers(config.values, key)
+	return getIntegers(&(config.configSection), key)
 }
 
 /*
@@ -379,15 +580,43 @@ Floats looks for an array of floats under the provided key.
 If no matches are found nil is returned.
 */
 func (config *Config) Floats(key string) []float64 {
-	return getFloats(config.values, key)
+	return getFloats(&(config.configSection), key)
 }
 
 func (config *Config) sectionForName(sectionName string) *configSection {
-	if len(sectionName) == 0 || sectionName == config.name {
+	if len(sectionName) == 0 {
+		return &(config.configSection)
+	}
+
+	if config.Options.sameSection(sectionName, config.name) {
 		return &(config.configSection)
 	}
 
-	return config.sections[sectionName]
+	if !config.Options.CaseInsensitiveSections {
+		return config.sections[sectionName]
+	}
+
+	var buf [64]byte
+	return config.sections[string(appendLower(buf[:0], sectionName))]
+}
+
+//Append the lower case form of s to dst, without allocating if s is ASCII and fits in dst
+func appendLower[S string | []byte](dst []byte, s S) []byte {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if c >= utf8.RuneSelf {
+			return append(dst[:0], strings.ToLower(string(s))...)
+		}
+
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+
+		dst = append(dst, c)
+	}
+
+	return dst
 }
 
 /*
@@ -399,7 +628,7 @@ func (config *Config) StringFromSection(sectionName string, key string, def stri
 	section := config.sectionForName(sectionName)
 
 	if section != nil {
-		return getString(section.values, key, def)
+		return getString(section, key, def)
 	}
 
 	return def
@@ -414,7 +643,7 @@ func (config *Config) BooleanFromSection(sectionName string, key string, def boo
 	section := config.sectionForName(sectionName)
 
 	if section != nil {
-		return getBoolean(section.values, key, def)
+		return getBoolean(section, key, def)
 	}
 
 	return def
@@ -429,7 +658,7 @@ func (config *Config) IntegerFromSection(sectionName string, key string, def int
 	section := config.sectionForName(sectionName)
 
 	if section != nil {
-		return getInteger(section.values, key, def)
+		return getInteger(section, key, def)
 	}
 
 	return def
@@ -444,7 +673,7 @@ func (config *Config) FloatFromSection(sectionName string, key string, def float
 	section := config.sectionForName(sectionName)
 
 	if section != nil {
-		return getFloat(section.values, key, def)
+		return getFloat(section, key, def)
 	}
 
 	return def
@@ -460,7 +689,7 @@ func (config *Config) StringsFromSection(sectionName string, key string) []strin
 	section := config.sectionForName(sectionName)
 
 	if section != nil {
-		return getStrings(section.values, key)
+		return getStrings(section, key)
 	}
 
 	return nil
@@ -474,7 +703,7 @@ func (config *Config) IntegersFromSection(sectionName string, key string) []int6
 	section := config.sectionForName(sectionName)
 
 	if section != nil {
-		return getIntegers(section.values, key)
+		return getIntegers(section, key)
 	}
 
 	return nil
@@ -490,7 +719,7 @@ func (config *Config) FloatsFromSection(sectionName string, key string) []float6
 	section := config.sectionForName(sectionName)
 
 	if section != nil {
-		return getFloats(section.values, key)
+		return getFloats(section, key)
 	}
 
 	return nil
@@ -505,9 +734,19 @@ DataFromSection reads the values of a section into a struct. The values should b
   []int64
   float64
   []float64
-Values that are missing in the section are not set, and values that are missing in the
+  time.Duration
+  []time.Duration
+  map[string]T, where T is one of the single value types above
+Values that are missing in the section are not set, unless the field has a default tag, as in
+`default:"30s"`, which is read the way a value in the file would be. Values that are missing in the
 struct but present in the section are ignored.
 
+Array fields can be read from a single delimited value by adding a sep tag to the field,
+as in `sep:","`, see StringsSep.
+
+Fields of other types are skipped, use Decoder.DecodeSection to have them reported, or to read
+them with a decoder added by Decoder.RegisterDecoder.
+
 If the section name matches the config.name or "" the global data is searched.
 */
 func (config *Config) DataFromSection(sectionName string, data interface{}) bool {
@@ -517,8 +756,6 @@ func (config *Config) DataFromSection(sectionName string, data interface{}) bool
 		return false
 	}
 
-	values := section.values
-
 	fields := reflect.ValueOf(data).Elem()
 	dataType := fields.Type()
 
@@ -532,52 +769,74 @@ func (config *Config) DataFromSection(sectionName string, data interface{}) bool
 		fieldType := dataType.Field(i)
 		fieldName := fieldType.Name
 
+		config.defaultField(section, sectionName, fieldType, field)
+
 		switch field.Type().Kind() {
 		case reflect.Bool:
-			field.SetBool(getBoolean(values, fieldName, field.Interface().(bool)))
+			field.SetBool(getBoolean(section, fieldName, field.Interface().(bool)))
 		case reflect.Int64:
-			field.SetInt(getInteger(values, fieldName, field.Interface().(int64)))
+			if field.Type() == durationType {
+				if val, err := lookupValue(section, sectionName, fieldName, time.ParseDuration); err == nil {
+					field.SetInt(int64(val))
+				}
+			} else {
+				field.SetInt(getInteger(section, fieldName, field.Interface().(int64)))
+			}
 		case reflect.Float64:
-			field.SetFloat(getFloat(values, fieldName, field.Interface().(float64)))
+			field.SetFloat(getFloat(section, fieldName, field.Interface().(float64)))
 		case reflect.String:
-			field.SetString(getString(values, fieldName, field.Interface().(string)))
+			field.SetString(getString(section, fieldName, field.Interface().(string)))
 		case reflect.Array, reflect.Slice:
+			values := getArray(section, fieldName)
+
+			if sep, ok := fieldType.Tag.Lookup("sep"); ok {
+				values = getSplitArray(section, fieldName, sep)
+			}
+
 			switch fieldType.Type.Elem().Kind() {
 			case reflect.Int64:
-				ints := getIntegers(values, fieldName)
-				if ints != nil {
-					field.Set(reflect.ValueOf(ints))
+				if fieldType.Type.Elem() == durationType {
+					if durations, err := parseValues(values, sectionName, fieldName, time.ParseDuration); err == nil {
+						field.Set(reflect.ValueOf(durations))
+					}
+				} else {
+					ints := toIntegers(values)
+					if ints != nil {
+						field.Set(reflect.ValueOf(ints))
+					}
 				}
 			case reflect.Float64:
-				floats := getFloats(values, fieldName)
+				floats := toFloats(values)
 				if floats != nil {
 					field.Set(reflect.ValueOf(floats))
 				}
 			case reflect.String:
-				strings := getStrings(values, fieldName)
+				strings := toStrings(values)
 				if strings != nil {
 					field.Set(reflect.ValueOf(strings))
 				}
 			}
+		case reflect.Map:
+			values := getStringMap(section, fieldName)
+			if values != nil {
+				if m, err := decodeMap(values, fieldType.Type, config.Options.parseBoolean); err == nil {
+					field.Set(m)
+				}
+			}
 		}
 	}
 	return true
 }
 
 /*
-Keys returns all of the global keys in the config.
+Keys returns all of the global keys in the config. Keys are returned as they were first spelled in the file.
 */
 func (config *Config) Keys() []string {
-	keys := make([]string, 0, len(config.values))
-	for key := range config.values {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-	return keys
+	return config.sortedKeys()
 }
 
 /*
-KeysForSection returns all of the keys found in the section named sectionName.
+KeysForSection returns all of the keys found in the section named sectionName. Keys are returned as they were first spelled in the file.
 
 If the section name matches the config.name or "" the global data is searched.
 */
@@ -585,12 +844,7 @@ func (config *Config) KeysForSection(sectionName string) []string {
 	section := config.sectionForName(sectionName)
 
 	if section != nil {
-		keys := make([]string, 0, len(section.values))
-		for key := range section.values {
-			keys = append(keys, key)
-		}
-		sort.Strings(keys)
-		return keys
+		return section.sortedKeys()
 	}
 
 	return nil
@@ -599,11 +853,13 @@ func (config *Config) KeysForSection(sectionName string) []string {
 /*
 SectionNames returns the names for each of the sections in a config structure. If the config was assigned
 a name, that name is included in the list. If the name is not set, then only explicitely named sections are returned.
+
+Names are returned as they were first spelled in the file, even when sections are compared without regard to case.
 */
 func (config *Config) SectionNames() []string {
 	sectionNames := make([]string, 0, len(config.sections))
-	for name := range config.sections {
-		sectionNames = append(sectionNames, name)
+	for _, section := range config.sections {
+		sectionNames = append(sectionNames, section.name)
 	}
 
 	if len(config.name) > 0 {
@@ -614,3 +870,38 @@ func (config *Config) SectionNames() []string {
 
 	return sectionNames
 }
+
+/*
+KeysInOrder returns all of the keys found in the section named sectionName in the order they first
+appeared in the file. Keys are returned as they were first spelled in the file.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) KeysInOrder(sectionName string) []string {
+	section := config.sectionForName(sectionName)
+
+	if section != nil {
+		return section.orderedKeys()
+	}
+
+	return nil
+}
+
+/*
+SectionsInOrder returns the names for each of the sections in a config structure in the order they first
+appeared in the file. If the config was assigned a name, that name is first in the list, since
+global values appear before any section.
+*/
+func (config *Config) SectionsInOrder() []string {
+	sectionNames := make([]string, 0, len(config.sectionOrder)+1)
+
+	if len(config.name) > 0 {
+		sectionNames = append(sectionNames, config.name)
+	}
+
+	for _, section := range config.sectionOrder {
+		sectionNames = append(sectionNames, section.name)
+	}
+
+	return sectionNames
+}
@@ -1,6 +1,8 @@
 package mini
 
 import (
+	"bytes"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"path"
@@ -42,7 +44,7 @@ false=false
 	assert.Equal(t, config.String("second", ""), "beta", "Read value of second wrong")
 	assert.Equal(t, config.String("third", ""), "gamma bamma", "Read value of third wrong")
 	assert.Equal(t, config.String("fourth", ""), "delta", "Read value of fourth wrong")
-	assert.Equal(t, config.Integer("int", 0), 32, "Read value of int wrong")
+	assert.Equal(t, config.Integer("int", 0), int64(32), "Read value of int wrong")
 	assert.Equal(t, config.Float("float", 0), 3.14, "Read value of float wrong")
 	assert.Equal(t, config.Boolean("true", false), true, "Read true wrong")
 	assert.Equal(t, config.Boolean("false", true), false, "Read false wrong")
@@ -71,7 +73,7 @@ false=false
 	assert.Equal(t, config.String("second", ""), "beta", "Read value of second wrong")
 	assert.Equal(t, config.String("third", ""), "gamma bamma", "Read value of third wrong")
 	assert.Equal(t, config.String("fourth", ""), "delta", "Read value of fourth wrong")
-	assert.Equal(t, config.Integer("int", 0), 32, "Read value of int wrong")
+	assert.Equal(t, config.Integer("int", 0), int64(32), "Read value of int wrong")
 	assert.Equal(t, config.Float("float", 0), 3.14, "Read value of float wrong")
 	assert.Equal(t, config.Boolean("true", false), true, "Read true wrong")
 	assert.Equal(t, config.Boolean("false", true), false, "Read false wrong")
@@ -98,6 +100,35 @@ FourTh = 'delta'`
 	assert.Equal(t, len(config.Keys()), 4, "Case ins ini contains 4 fields")
 }
 
+func TestKeysKeepSpelling(t *testing.T) {
+
+	simpleIni := `MaxConnections=10
+hostName=alpha
+Hosts[]=one
+hosts[]=two
+[section]
+TimeOut=30
+timeout=40`
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(simpleIni))
+
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	keys := config.Keys()
+	assert.Equal(t, len(keys), 3, "ini contains 3 fields")
+	assert.Equal(t, keys[0], "hostName", "Key should keep its spelling")
+	assert.Equal(t, keys[1], "Hosts", "Array key should keep its first spelling")
+	assert.Equal(t, keys[2], "MaxConnections", "Key should keep its spelling")
+
+	keys = config.KeysForSection("section")
+	assert.Equal(t, len(keys), 1, "section contains 1 field")
+	assert.Equal(t, keys[0], "TimeOut", "Key should keep its first spelling")
+
+	assert.Equal(t, config.Integer("maxconnections", 0), int64(10), "Lookups should be case insensitive")
+	assert.Equal(t, len(config.Strings("HOSTS")), 2, "Lookups should be case insensitive")
+	assert.Equal(t, config.IntegerFromSection("section", "timeout", 0), int64(40), "Later values should replace earlier ones")
+}
+
 func TestArrayOfStrings(t *testing.T) {
 
 	simpleIni := `key[]=one
@@ -134,12 +165,12 @@ noarray=3`
 	val := config.Integers("key")
 
 	assert.Equal(t, len(val), 2, "Array for keys should have 2 values")
-	assert.Equal(t, val[0], 1, "Read value of first wrong")
-	assert.Equal(t, val[1], 2, "Read value of second wrong")
+	assert.Equal(t, val[0], int64(1), "Read value of first wrong")
+	assert.Equal(t, val[1], int64(2), "Read value of second wrong")
 
 	val = config.Integers("noarray")
 	assert.Equal(t, len(val), 1, "Array for noarray should have 1 value")
-	assert.Equal(t, val[0], 3, "Read value of noarray wrong")
+	assert.Equal(t, val[0], int64(3), "Read value of noarray wrong")
 
 	assert.Equal(t, len(config.Keys()), 2, "IntArray test contains 2 fields")
 }
@@ -196,14 +227,14 @@ false=true
 	assert.Equal(t, config.String("second", ""), "beta", "Read value of second wrong")
 	assert.Equal(t, config.String("third", ""), "gamma bamma", "Read value of third wrong")
 	assert.Equal(t, config.String("fourth", ""), "delta", "Read value of fourth wrong")
-	assert.Equal(t, config.Integer("int", 0), 32, "Read value of int wrong")
+	assert.Equal(t, config.Integer("int", 0), int64(32), "Read value of int wrong")
 	assert.Equal(t, config.Float("float", 0), 3.14, "Read value of float wrong")
 	assert.Equal(t, config.Boolean("true", false), true, "Read true wrong")
 	assert.Equal(t, config.Boolean("false", true), false, "Read false wrong")
 
 	assert.Equal(t, config.StringFromSection("section", "first", ""), "raz", "Read value of first from section wrong")
 	assert.Equal(t, config.StringFromSection("section", "second", ""), "dba", "Read value of second from section wrong")
-	assert.Equal(t, config.IntegerFromSection("section", "int", 0), 124, "Read value of int in section wrong")
+	assert.Equal(t, config.IntegerFromSection("section", "int", 0), int64(124), "Read value of int in section wrong")
 	assert.Equal(t, config.FloatFromSection("section", "float", 0), 1222.7, "Read value of float in section wrong")
 	assert.Equal(t, config.BooleanFromSection("section", "true", true), false, "Read true in section wrong")
 	assert.Equal(t, config.BooleanFromSection("section", "false", false), true, "Read false in section wrong")
@@ -254,12 +285,12 @@ noarray=3`
 	val := config.IntegersFromSection("section", "key")
 
 	assert.Equal(t, len(val), 2, "Array for keys should have 2 values")
-	assert.Equal(t, val[0], 1, "Read value of first wrong")
-	assert.Equal(t, val[1], 2, "Read value of second wrong")
+	assert.Equal(t, val[0], int64(1), "Read value of first wrong")
+	assert.Equal(t, val[1], int64(2), "Read value of second wrong")
 
 	val = config.IntegersFromSection("section", "noarray")
 	assert.Equal(t, len(val), 1, "Array for noarray should have 1 value")
-	assert.Equal(t, val[0], 3, "Read value of noarray wrong")
+	assert.Equal(t, val[0], int64(3), "Read value of noarray wrong")
 
 	assert.Equal(t, len(config.KeysForSection("section")), 2, "IntArray section test contains 2 fields")
 }
@@ -311,25 +342,25 @@ float=124.3`
 	assert.Nil(t, err, "Sectioned configuration should load without error.")
 
 	assert.Equal(t, config.String("first", ""), "alpha", "Read value of first wrong")
-	assert.Equal(t, config.Integer("int", 0), 32, "Read value of int wrong")
+	assert.Equal(t, config.Integer("int", 0), int64(32), "Read value of int wrong")
 	assert.Equal(t, config.Float("float", 0), 3.14, "Read value of float wrong")
 
 	assert.Equal(t, config.StringFromSection("", "first", ""), "alpha", "Read value of first wrong")
-	assert.Equal(t, config.IntegerFromSection("", "int", 0), 32, "Read value of int wrong")
+	assert.Equal(t, config.IntegerFromSection("", "int", 0), int64(32), "Read value of int wrong")
 	assert.Equal(t, config.FloatFromSection("", "float", 0), 3.14, "Read value of float wrong")
 
 	config.SetName("section_zero")
 
 	assert.Equal(t, config.StringFromSection("section_zero", "first", ""), "alpha", "Read value of first wrong")
-	assert.Equal(t, config.IntegerFromSection("section_zero", "int", 0), 32, "Read value of int wrong")
+	assert.Equal(t, config.IntegerFromSection("section_zero", "int", 0), int64(32), "Read value of int wrong")
 	assert.Equal(t, config.FloatFromSection("section_zero", "float", 0), 3.14, "Read value of float wrong")
 
 	assert.Equal(t, config.StringFromSection("section_one", "first", ""), "raz", "Read value of first from section wrong")
-	assert.Equal(t, config.IntegerFromSection("section_one", "int", 0), 124, "Read value of int in section wrong")
+	assert.Equal(t, config.IntegerFromSection("section_one", "int", 0), int64(124), "Read value of int in section wrong")
 	assert.Equal(t, config.FloatFromSection("section_one", "float", 0), 1222.7, "Read value of float in section wrong")
 
 	assert.Equal(t, config.StringFromSection("section_two", "first", ""), "one", "Read value of first from section wrong")
-	assert.Equal(t, config.IntegerFromSection("section_two", "int", 0), 555, "Read value of int in section wrong")
+	assert.Equal(t, config.IntegerFromSection("section_two", "int", 0), int64(555), "Read value of int in section wrong")
 	assert.Equal(t, config.FloatFromSection("section_two", "float", 0), 124.3, "Read value of float in section wrong")
 
 	assert.Equal(t, len(config.Keys()), 3, "Section ini contains 3 fields")
@@ -382,6 +413,92 @@ float=124.3`
 
 }
 
+func TestCaseInsensitiveSections(t *testing.T) {
+
+	simpleIni := `[Database]
+host=alpha
+
+[DATABASE]
+port=5432
+
+[other]
+first=raz`
+
+	config := &Config{Options: Options{CaseInsensitiveSections: true}}
+	err := config.InitializeFromReader(strings.NewReader(simpleIni))
+
+	assert.Nil(t, err, "Sectioned configuration should load without error.")
+
+	assert.Equal(t, config.StringFromSection("database", "host", ""), "alpha", "Read value of host wrong")
+	assert.Equal(t, config.IntegerFromSection("database", "port", 0), int64(5432), "Read value of port wrong")
+	assert.Equal(t, config.StringFromSection("OTHER", "first", ""), "raz", "Read value of first wrong")
+
+	sectionNames := config.SectionNames()
+
+	assert.Equal(t, len(sectionNames), 2, "Read section name array wrong")
+	assert.Equal(t, sectionNames[0], "Database", "Section name should keep its first spelling")
+	assert.Equal(t, sectionNames[1], "other", "Read section name wrong")
+
+	config.SetName("Globals")
+	assert.Equal(t, len(config.KeysForSection("GLOBALS")), 0, "Config name should be case insensitive")
+	assert.Nil(t, config.KeysForSection("globalz"), "missing section should have no keys")
+}
+
+func TestCaseSensitiveSections(t *testing.T) {
+
+	simpleIni := `[Database]
+host=alpha
+
+[database]
+host=beta`
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(simpleIni))
+
+	assert.Nil(t, err, "Sectioned configuration should load without error.")
+
+	assert.Equal(t, config.StringFromSection("Database", "host", ""), "alpha", "Read value of host wrong")
+	assert.Equal(t, config.StringFromSection("database", "host", ""), "beta", "Read value of host wrong")
+	assert.Equal(t, config.StringFromSection("DATABASE", "host", "none"), "none", "Sections should be case sensitive by default")
+	assert.Equal(t, len(config.SectionNames()), 2, "Read section name array wrong")
+}
+
+func TestKeysAndSectionsInOrder(t *testing.T) {
+
+	simpleIni := `zebra=1
+Apple=2
+middle[]=3
+[stage_three]
+run=c
+build=d
+[stage_one]
+run=a
+[stage_two]
+run=b
+[stage_three]
+alpha=e
+run=f`
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(simpleIni))
+
+	assert.Nil(t, err, "Sectioned configuration should load without error.")
+
+	keys := config.KeysInOrder("")
+	assert.Equal(t, keys, []string{"zebra", "Apple", "middle"}, "Global keys should be in file order")
+
+	keys = config.KeysInOrder("stage_three")
+	assert.Equal(t, keys, []string{"run", "build", "alpha"}, "Split section keys should be in file order")
+
+	assert.Nil(t, config.KeysInOrder("stage_four"), "missing section should have no keys")
+
+	sections := config.SectionsInOrder()
+	assert.Equal(t, sections, []string{"stage_three", "stage_one", "stage_two"}, "Sections should be in file order")
+
+	config.SetName("globals")
+	sections = config.SectionsInOrder()
+	assert.Equal(t, sections, []string{"globals", "stage_three", "stage_one", "stage_two"}, "Config name should come first")
+	assert.Equal(t, config.KeysInOrder("globals"), []string{"zebra", "Apple", "middle"}, "Config name should find global keys")
+}
+
 func TestSplitSection(t *testing.T) {
 
 	simpleIni := `first=alpha
@@ -405,15 +522,15 @@ float=1222.7`
 	assert.Nil(t, err, "Sectioned configuration should load without error.")
 
 	assert.Equal(t, config.String("first", ""), "alpha", "Read value of first wrong")
-	assert.Equal(t, config.Integer("int", 0), 32, "Read value of int wrong")
+	assert.Equal(t, config.Integer("int", 0), int64(32), "Read value of int wrong")
 	assert.Equal(t, config.Float("float", 0), 3.14, "Read value of float wrong")
 
 	assert.Equal(t, config.StringFromSection("section_one", "first", ""), "raz", "Read value of first from section wrong")
-	assert.Equal(t, config.IntegerFromSection("section_one", "int", 0), 124, "Read value of int in section wrong")
+	assert.Equal(t, config.IntegerFromSection("section_one", "int", 0), int64(124), "Read value of int in section wrong")
 	assert.Equal(t, config.FloatFromSection("section_one", "float", 0), 1222.7, "Read value of float in section wrong")
 
 	assert.Equal(t, config.StringFromSection("section_two", "first", ""), "one", "Read value of first from section wrong")
-	assert.Equal(t, config.IntegerFromSection("section_two", "int", 0), 555, "Read value of int in section wrong")
+	assert.Equal(t, config.IntegerFromSection("section_two", "int", 0), int64(555), "Read value of int in section wrong")
 	assert.Equal(t, config.FloatFromSection("section_two", "float", 0), 124.3, "Read value of float in section wrong")
 
 	assert.Equal(t, len(config.Keys()), 3, "Section ini contains 3 fields")
@@ -446,12 +563,12 @@ third=\`
 
 	assert.Equal(t, config.String("second", "beta"), "beta", "Read default value of first wrong")
 	assert.Equal(t, config.String("third", "gamma"), "gamma", "Read default value of too short a string")
-	assert.Equal(t, config.Integer("int", 32), 32, "Default value of int wrong")
+	assert.Equal(t, config.Integer("int", 32), int64(32), "Default value of int wrong")
 	assert.Equal(t, config.Float("float", 3.14), 3.14, "Default value of float wrong")
 	assert.Equal(t, config.Boolean("bool", true), true, "Default value of bool wrong")
 
 	assert.Equal(t, config.String("", "test"), "test", "Nil key should result in empty value")
-	assert.Equal(t, config.Integer("", 32), 32, "Default value of int wrong for empty key")
+	assert.Equal(t, config.Integer("", 32), int64(32), "Default value of int wrong for empty key")
 	assert.Equal(t, config.Float("", 3.14), 3.14, "Default value of float wrong for empty key")
 	assert.Equal(t, config.Boolean("", true), true, "Default value of bool wrong for empty key")
 
@@ -472,7 +589,7 @@ floatarray[]=blap`
 	assert.Nil(t, err, "Configuration should load without error.")
 
 	assert.Equal(t, config.String("second", "beta"), "beta", "Read default value of first wrong")
-	assert.Equal(t, config.Integer("int", 32), 32, "Default value of int wrong")
+	assert.Equal(t, config.Integer("int", 32), int64(32), "Default value of int wrong")
 	assert.Equal(t, config.Float("float", 3.14), 3.14, "Default value of float wrong")
 	assert.Equal(t, config.Boolean("bool", true), true, "Default value of bool wrong")
 
@@ -511,12 +628,12 @@ first=alpha`
 	assert.Nil(t, err, "Configuration should load without error.")
 
 	assert.Equal(t, config.StringFromSection("section", "second", "beta"), "beta", "Read default value of first wrong")
-	assert.Equal(t, config.IntegerFromSection("section", "int", 32), 32, "Default value of int wrong")
+	assert.Equal(t, config.IntegerFromSection("section", "int", 32), int64(32), "Default value of int wrong")
 	assert.Equal(t, config.FloatFromSection("section", "float", 3.14), 3.14, "Default value of float wrong")
 	assert.Equal(t, config.BooleanFromSection("section", "bool", true), true, "Default value of bool wrong")
 
 	assert.Equal(t, config.StringFromSection("section-1", "second", "beta"), "beta", "Missing section for first wrong")
-	assert.Equal(t, config.IntegerFromSection("section-1", "int", 32), 32, "Missing section for int wrong")
+	assert.Equal(t, config.IntegerFromSection("section-1", "int", 32), int64(32), "Missing section for int wrong")
 	assert.Equal(t, config.FloatFromSection("section-1", "float", 3.14), 3.14, "Missing section for float wrong")
 	assert.Equal(t, config.BooleanFromSection("section-1", "bool", true), true, "Missing section for bool wrong")
 
@@ -577,11 +694,11 @@ F64s[]=22.0
 
 	assert.Equal(t, data.First, "alpha", "Read value of first wrong")
 	assert.Equal(t, data.Second, "beta", "Read value of second wrong")
-	assert.Equal(t, data.L, -32, "Read value of int wrong")
+	assert.Equal(t, data.L, int64(-32), "Read value of int wrong")
 	assert.Equal(t, data.F64, 3.14, "Read value of float wrong")
 	assert.Equal(t, data.Flag, true, "Read true wrong")
 	assert.Equal(t, data.Missing, "hello world", "Read value of missing wrong")
-	assert.Equal(t, data.MissingInt, 33, "Read false wrong")
+	assert.Equal(t, data.MissingInt, int64(33), "Read false wrong")
 	assert.Nil(t, data.MissingArray, "Missing array Should be nil")
 	assert.Equal(t, data.private, "", "private value in struct should be ignored")
 
@@ -593,8 +710,8 @@ F64s[]=22.0
 
 	assert.NotNil(t, data.LS, "ints should not be nil")
 	assert.Equal(t, len(data.LS), 2, "Read wrong length of ints array")
-	assert.Equal(t, data.LS[0], 1, "Read ints array wrong")
-	assert.Equal(t, data.LS[1], 2, "Read ints array wrong")
+	assert.Equal(t, data.LS[0], int64(1), "Read ints array wrong")
+	assert.Equal(t, data.LS[1], int64(2), "Read ints array wrong")
 
 	assert.NotNil(t, data.F64s, "floats should not be nil")
 	assert.Equal(t, len(data.F64s), 2, "Read wrong length of floats array")
@@ -760,3 +877,192 @@ float=1222.7`
 		}
 	}
 }
+
+const getterIni = `first=alpha
+int=32
+float=3.14
+bool=true
+escaped="\thello"
+
+[Section_One]
+MaxConnections=124`
+
+//Build an ini file with at least the given number of lines, using sections, arrays, maps and comments
+func generateIni(lines int) string {
+	var buf bytes.Buffer
+
+	for i := 0; i*14 < lines; i++ {
+		fmt.Fprintf(&buf, "\n[section_%d]\n", i)
+		fmt.Fprintf(&buf, "; settings for section %d\n", i)
+		fmt.Fprintf(&buf, "Name = \"section number %d\"\n", i)
+		fmt.Fprintf(&buf, "MaxConnections=%d\n", i*10)
+		fmt.Fprintf(&buf, "ratio = %d.5\n", i)
+		fmt.Fprintf(&buf, "enabled=true\n")
+		fmt.Fprintf(&buf, "hosts = [a%d, b%d, c%d]\n", i, i, i)
+		for j := 0; j < 4; j++ {
+			fmt.Fprintf(&buf, "Ports[]=%d\n", 8000+j)
+		}
+		fmt.Fprintf(&buf, "headers[Content-Type]=text/html\n")
+		fmt.Fprintf(&buf, "headers[X-Section]=%d\n", i)
+		fmt.Fprintf(&buf, "path=\"c:\\\\data\\\\%d\"\n", i)
+	}
+
+	return buf.String()
+}
+
+func benchmarkLoadLines(b *testing.B, lines int) {
+	ini := generateIni(lines)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(ini)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := LoadConfigurationFromReader(strings.NewReader(ini))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoad10kLines(b *testing.B) {
+	benchmarkLoadLines(b, 10000)
+}
+
+func BenchmarkLoad100kLines(b *testing.B) {
+	benchmarkLoadLines(b, 100000)
+}
+
+func BenchmarkLoad1MLines(b *testing.B) {
+	benchmarkLoadLines(b, 1000000)
+}
+
+func TestLoadGeneratedIni(t *testing.T) {
+
+	config := &Config{Options: Options{InlineLists: true}}
+	err := config.InitializeFromReader(strings.NewReader(generateIni(1000)))
+
+	assert.Nil(t, err, "Generated configuration should load without error.")
+
+	assert.Equal(t, config.StringFromSection("section_3", "name", ""), "section number 3", "Read value of name wrong")
+	assert.Equal(t, config.IntegerFromSection("section_3", "maxconnections", 0), int64(30), "Read value of MaxConnections wrong")
+	assert.Equal(t, config.FloatFromSection("section_3", "ratio", 0), 3.5, "Read value of ratio wrong")
+	assert.Equal(t, config.StringsFromSection("section_3", "hosts"), []string{"a3", "b3", "c3"}, "Read value of hosts wrong")
+	assert.Equal(t, config.IntegersFromSection("section_3", "ports"), []int64{8000, 8001, 8002, 8003}, "Read value of ports wrong")
+	assert.Equal(t, config.MapFromSection("section_3", "headers")["X-Section"], "3", "Read value of headers wrong")
+	assert.Equal(t, config.StringFromSection("section_3", "path", ""), `c:\data\3`, "Read value of path wrong")
+	assert.Equal(t, config.KeysInOrder("section_3"), []string{"Name", "MaxConnections", "ratio", "enabled", "hosts", "Ports", "headers", "path"}, "Read keys wrong")
+}
+
+func TestGettersDontAllocate(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(getterIni))
+
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	folded := &Config{Options: Options{CaseInsensitiveSections: true}}
+	err = folded.InitializeFromReader(strings.NewReader(getterIni))
+
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	var first, escaped, missing string
+	var integer, connections, anyCase, foldedConnections int64
+	var float float64
+	var boolean, found bool
+
+	allocs := testing.AllocsPerRun(100, func() {
+		first = config.String("first", "")
+		escaped = config.String("escaped", "")
+		integer = config.Integer("int", 0)
+		float = config.Float("float", 0)
+		boolean = config.Boolean("bool", false)
+		missing = config.String("missing", "")
+		connections = config.IntegerFromSection("Section_One", "maxconnections", 0)
+		anyCase = config.IntegerFromSection("Section_One", "MaxConnections", 0)
+		_, found = config.Lookup("Section_One", "MAXCONNECTIONS")
+		foldedConnections = folded.IntegerFromSection("section_one", "maxconnections", 0)
+	})
+
+	assert.Equal(t, allocs, 0.0, "Getters should not allocate")
+
+	assert.Equal(t, first, "alpha", "Read value of first wrong")
+	assert.Equal(t, escaped, "\thello", "Read value of escaped wrong")
+	assert.Equal(t, integer, int64(32), "Read value of int wrong")
+	assert.Equal(t, float, 3.14, "Read value of float wrong")
+	assert.Equal(t, boolean, true, "Read value of bool wrong")
+	assert.Equal(t, missing, "", "Missing key should return the default")
+	assert.Equal(t, connections, int64(124), "Read value of maxconnections wrong")
+	assert.Equal(t, anyCase, int64(124), "Keys should be found in any case")
+	assert.True(t, found, "Lookup should find the key")
+	assert.Equal(t, foldedConnections, int64(124), "Section should be found in any case with CaseInsensitiveSections")
+}
+
+func BenchmarkString(b *testing.B) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(getterIni))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		config.String("escaped", "")
+	}
+}
+
+func BenchmarkInteger(b *testing.B) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(getterIni))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		config.Integer("int", 0)
+	}
+}
+
+func BenchmarkFloat(b *testing.B) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(getterIni))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		config.Float("float", 0)
+	}
+}
+
+func BenchmarkBoolean(b *testing.B) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(getterIni))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		config.Boolean("bool", false)
+	}
+}
+
+func BenchmarkIntegerFromSection(b *testing.B) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(getterIni))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		config.IntegerFromSection("Section_One", "MaxConnections", 0)
+	}
+}
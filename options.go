@@ -0,0 +1,161 @@
+package mini
+
+import (
+	"strconv"
+	"strings"
+)
+
+/*
+Options controls how a Config parses and searches its data. Options should be set before
+the config is initialized, changing them afterwards may leave the config unable to find
+data that it has already loaded.
+
+The zero value for Options is the default behavior.
+*/
+type Options struct {
+	/*
+		CaseInsensitiveSections compares section names without regard to case, just like keys, so
+		[Database] can be read using the name "database". By default section names are compared exactly.
+		The spelling from the first occurrence of a section is kept for SectionNames.
+	*/
+	CaseInsensitiveSections bool
+
+	/*
+		InheritValues makes sections fall back to their parent when a key is missing. The parent of
+		[server.http] is [server], if it exists, and the global values are the parent of every top level section.
+	*/
+	InheritValues bool
+
+	/*
+		Extends lets a section inherit the keys of another section, either with a header like [prod : base] or
+		with the key extends=base inside the section. Sections can be extended through several levels, a cycle
+		is reported as an error when the config is initialized. A missing key is looked up in the section, then in
+		the sections it extends, then in its parent and finally in the global values, the parents of the extended
+		sections aren't searched.
+	*/
+	Extends bool
+
+	/*
+		InlineLists reads values written as [a, b, "c,d"] as arrays. Without it such values are text, so
+		an address like [::1] is read as it is written. A value in quotes, like "[::1]", is always text.
+	*/
+	InlineLists bool
+
+	/*
+		ExtendedBooleans accepts yes/no, on/off and enabled/disabled, in any case, as booleans along with the
+		values understood by strconv.ParseBool.
+	*/
+	ExtendedBooleans bool
+
+	/*
+		BooleanParser, if set, replaces the built in conversion of values to booleans, including ExtendedBooleans.
+		Values that it returns an error for are treated like any other value that can't be parsed.
+	*/
+	BooleanParser func(value string) (bool, error)
+
+	/*
+		SecretKeys lists the keys whose values are secret. Secret values are replaced by Redacted in
+		Value.String, ToMap and error messages, the getters still return the real value. Each entry is a
+		pattern for path.Match, compared without regard to case against the key and against section.key,
+		so "password", "db.password" and "*token*" can all be used.
+	*/
+	SecretKeys []string
+
+	/*
+		SecretResolvers expands values written as references, like file:///run/secrets/db, by the
+		scheme of the reference. References are resolved the first time they are read and the secret is
+		kept, so a changed secret is only picked up by reloading the config. They are always secret.
+	*/
+	SecretResolvers map[string]SecretResolver
+
+	/*
+		Decrypter decrypts values written as ENC[...] when the file is loaded, see KeyFile. Decrypted values
+		are secret. Without a Decrypter encrypted values are read as text.
+	*/
+	Decrypter Decrypter
+
+	/*
+		DuplicateKeys chooses what happens when a key, or a name in a map value, is set twice in a section.
+		By default the last value wins.
+	*/
+	DuplicateKeys DuplicatePolicy
+
+	/*
+		DuplicateSections chooses what happens when a section appears more than once in a file.
+		By default the occurrences are merged.
+	*/
+	DuplicateSections DuplicatePolicy
+
+	/*
+		MixedKeys chooses what happens when a key is written in more than one of the forms key=value, key[]=value
+		and key[name]=value in a section. By default the last form replaces the earlier ones.
+	*/
+	MixedKeys DuplicatePolicy
+
+	/*
+		Warn, if set, is called with each warning as it is found, as well as the warning being kept for
+		Config.Warnings.
+	*/
+	Warn func(Warning)
+
+	/*
+		InvalidUTF8 chooses what happens to lines that aren't valid UTF-8. By default they are read as they are,
+		UTF8Reject makes loading fail with the line and byte and UTF8Replace replaces the invalid bytes.
+	*/
+	InvalidUTF8 UTF8Policy
+}
+
+//Return the name used to store and find a section
+func (options *Options) sectionKey(name string) string {
+	if options.CaseInsensitiveSections {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+//Check if two section names refer to the same section
+func (options *Options) sameSection(a string, b string) bool {
+	if options.CaseInsensitiveSections {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+//Convert a value to a bool using the vocabulary chosen in the options
+func (options *Options) parseBoolean(value string) (bool, error) {
+	if options.BooleanParser != nil {
+		return options.BooleanParser(value)
+	}
+
+	if retVal, ok := options.booleanValue(value); ok {
+		return retVal, nil
+	}
+
+	return strconv.ParseBool(value) //for the error
+}
+
+//Convert a value to a bool without allocating an error for values that aren't booleans
+func (options *Options) booleanValue(value string) (bool, bool) {
+	if options.BooleanParser != nil {
+		retVal, err := options.BooleanParser(value)
+		return retVal, err == nil
+	}
+
+	switch value {
+	case "1", "t", "T", "true", "TRUE", "True":
+		return true, true
+	case "0", "f", "F", "false", "FALSE", "False":
+		return false, true
+	}
+
+	if options.ExtendedBooleans {
+		switch {
+		case strings.EqualFold(value, "yes"), strings.EqualFold(value, "on"), strings.EqualFold(value, "enabled"):
+			return true, true
+		case strings.EqualFold(value, "no"), strings.EqualFold(value, "off"), strings.EqualFold(value, "disabled"):
+			return false, true
+		}
+	}
+
+	return false, false
+}
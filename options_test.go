@@ -0,0 +1,84 @@
+package mini
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const booleanIni = `yes=yes
+no=NO
+on=On
+off=off
+enabled=enabled
+disabled=Disabled
+true=true
+zero=0
+nope=nope
+
+[section]
+debug=on`
+
+func TestDefaultBooleans(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(booleanIni))
+
+	assert.Nil(t, err, "Boolean configuration should load without error.")
+
+	assert.Equal(t, config.Boolean("yes", false), false, "yes isn't a boolean by default")
+	assert.Equal(t, config.Boolean("off", true), true, "off isn't a boolean by default")
+	assert.Equal(t, config.Boolean("true", false), true, "Read true wrong")
+	assert.Equal(t, config.Boolean("zero", true), false, "Read 0 wrong")
+}
+
+func TestExtendedBooleans(t *testing.T) {
+
+	config := &Config{Options: Options{ExtendedBooleans: true}}
+	err := config.InitializeFromReader(strings.NewReader(booleanIni))
+
+	assert.Nil(t, err, "Boolean configuration should load without error.")
+
+	assert.Equal(t, config.Boolean("yes", false), true, "Read yes wrong")
+	assert.Equal(t, config.Boolean("no", true), false, "Read no wrong")
+	assert.Equal(t, config.Boolean("on", false), true, "Read on wrong")
+	assert.Equal(t, config.Boolean("off", true), false, "Read off wrong")
+	assert.Equal(t, config.Boolean("enabled", false), true, "Read enabled wrong")
+	assert.Equal(t, config.Boolean("disabled", true), false, "Read disabled wrong")
+	assert.Equal(t, config.Boolean("true", false), true, "Read true wrong")
+	assert.Equal(t, config.Boolean("zero", true), false, "Read 0 wrong")
+	assert.Equal(t, config.Boolean("nope", true), true, "Default value of bool wrong on parse error")
+	assert.Equal(t, config.BooleanFromSection("section", "debug", false), true, "Read on in section wrong")
+
+	var data struct {
+		Debug bool
+	}
+
+	assert.True(t, config.DataFromSection("section", &data), "load should succeed")
+	assert.Equal(t, data.Debug, true, "Struct booleans should use the extended vocabulary")
+}
+
+func TestBooleanParser(t *testing.T) {
+
+	options := Options{
+		ExtendedBooleans: true,
+		BooleanParser: func(value string) (bool, error) {
+			switch value {
+			case "ja":
+				return true, nil
+			case "nein":
+				return false, nil
+			}
+			return false, errors.New("not a boolean")
+		},
+	}
+
+	config := &Config{Options: options}
+	err := config.InitializeFromReader(strings.NewReader("ja=ja\nnein=nein\nyes=yes"))
+
+	assert.Nil(t, err, "Boolean configuration should load without error.")
+
+	assert.Equal(t, config.Boolean("ja", false), true, "Read ja wrong")
+	assert.Equal(t, config.Boolean("nein", true), false, "Read nein wrong")
+	assert.Equal(t, config.Boolean("yes", false), false, "The parser should replace the extended vocabulary")
+}
@@ -0,0 +1,82 @@
+package mini
+
+import (
+	"sort"
+)
+
+/*
+Assignment is a line of the file that set a value.
+*/
+type Assignment struct {
+	Pos        Position
+	Occurrence int   //which appearance of a split section holds the line, starting at 1
+	Value      Value //the value written on the line, or the whole array or map for array and map values
+}
+
+/*
+Origin describes where a value came from.
+*/
+type Origin struct {
+	Assignment //the assignment that supplied the value, for arrays and maps the first entry
+
+	Section    string       //the section the value was found in, which differs from the one searched for inherited values
+	Entries    []Assignment //the entries of an array or map value, in the order they were written
+	Overridden []Assignment //earlier assignments to the key in the same section that were replaced, in the order they were written
+}
+
+/*
+Origin returns where the value of key in the section named sectionName was set, following the same rules as Lookup,
+along with any earlier assignments it replaced. The boolean is false if the key is missing.
+
+Repeated keys replace earlier values, and split sections are merged, so the same key can be set in several places.
+Occurrence tells which appearance of a split section supplied the value.
+*/
+func (config *Config) Origin(sectionName string, key string) (Origin, bool) {
+	if len(key) == 0 {
+		return Origin{}, false
+	}
+
+	lower := appendLower(nil, key)
+	val, section := config.sectionForName(sectionName).locate(lower)
+
+	if section == nil {
+		return Origin{}, false
+	}
+
+	origin := Origin{Assignment: assignment(val)}
+
+	if section != &(config.configSection) {
+		origin.Section = section.name
+	}
+
+	switch val.kind() {
+	case ArrayValue:
+		for _, entry := range val.entries() {
+			origin.Entries = append(origin.Entries, assignment(entry))
+		}
+	case MapValue:
+		for _, entry := range val.mapEntries() {
+			origin.Entries = append(origin.Entries, assignment(entry))
+		}
+
+		sortAssignments(origin.Entries)
+	}
+
+	for _, old := range section.overridden[string(lower)] {
+		origin.Overridden = append(origin.Overridden, assignment(old))
+	}
+
+	sortAssignments(origin.Overridden)
+	return origin, true
+}
+
+//Put assignments in file order, map names are kept in the order they first appeared but a later line can replace a name
+func sortAssignments(list []Assignment) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Pos.Line < list[j].Pos.Line
+	})
+}
+
+func assignment(val *value) Assignment {
+	return Assignment{Pos: val.position(), Occurrence: int(val.occurrence), Value: Value{val}}
+}
@@ -0,0 +1,76 @@
+package mini
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOrigin(t *testing.T) {
+
+	ini := `first=alpha
+first=beta
+[db]
+host=a
+[cache]
+size=1
+[db]
+host=b
+ports[]=1
+ports[]=2
+`
+	path := filepath.Join(t.TempDir(), "config.ini")
+	assert.Nil(t, os.WriteFile(path, []byte(ini), 0600), "Config should be written")
+
+	config, err := LoadConfiguration(path)
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	origin, ok := config.Origin("", "FIRST")
+	assert.True(t, ok, "Origin should find the key")
+	assert.Equal(t, origin.Pos, Position{File: path, Line: 2}, "Later value should win")
+	assert.Equal(t, origin.Value.String(), "beta", "Origin should hold the value")
+	assert.Equal(t, len(origin.Overridden), 1, "Earlier value should be overridden")
+	assert.Equal(t, origin.Overridden[0].Pos.Line, 1, "Overridden value should have its line")
+	assert.Equal(t, origin.Overridden[0].Value.String(), "alpha", "Overridden value should have its value")
+
+	origin, _ = config.Origin("db", "host")
+	assert.Equal(t, origin.Section, "db", "Origin should name the section")
+	assert.Equal(t, origin.Pos.Line, 8, "Value from the second occurrence should win")
+	assert.Equal(t, origin.Occurrence, 2, "Value should come from the second occurrence")
+	assert.Equal(t, origin.Overridden[0].Occurrence, 1, "Overridden value should be in the first occurrence")
+
+	origin, _ = config.Origin("db", "ports")
+	assert.Equal(t, origin.Pos.Line, 9, "Array should start at its first entry")
+	assert.Equal(t, len(origin.Entries), 2, "Array entries should be listed")
+	assert.Equal(t, origin.Entries[1].Pos.Line, 10, "Array entries should have their lines")
+	assert.Nil(t, origin.Overridden, "Array entries should not override each other")
+
+	_, ok = config.Origin("db", "size")
+	assert.False(t, ok, "Origin should not find missing keys")
+}
+
+func TestOriginInherited(t *testing.T) {
+
+	ini := `
+[base]
+timeout=5
+[server : base]
+opts[a]=1
+opts[b]=2
+opts[a]=3
+`
+	config := &Config{Options: Options{Extends: true}}
+	assert.Nil(t, config.InitializeFromReader(strings.NewReader(ini)), "Configuration should load without error.")
+
+	origin, ok := config.Origin("server", "timeout")
+	assert.True(t, ok, "Origin should follow extends")
+	assert.Equal(t, origin.Section, "base", "Origin should name the section the value came from")
+	assert.Equal(t, origin.Pos.Line, 3, "Inherited value should have its line")
+
+	origin, _ = config.Origin("server", "opts")
+	assert.Equal(t, len(origin.Entries), 2, "Map entries should be listed")
+	assert.Equal(t, origin.Entries[1].Pos.Line, 7, "Map entries should be in file order")
+	assert.Equal(t, origin.Overridden[0].Pos.Line, 5, "Replaced map entry should be overridden")
+}
@@ -0,0 +1,242 @@
+package mini
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+/*
+TokenKind identifies the kind of line a Scanner read.
+*/
+type TokenKind int
+
+const (
+	//SectionStart is a section header in the form [section]
+	SectionStart TokenKind = iota
+	//KeyValue is a key=value line
+	KeyValue
+	//ArrayItem is an array entry in the form key[]=value
+	ArrayItem
+	//MapItem is a map entry in the form key[name]=value
+	MapItem
+	//Comment is a line starting with # or ;
+	Comment
+	//Blank is an empty line, or a line containing only white space
+	Blank
+)
+
+func (kind TokenKind) String() string {
+	switch kind {
+	case SectionStart:
+		return "section"
+	case KeyValue:
+		return "key"
+	case ArrayItem:
+		return "array item"
+	case MapItem:
+		return "map item"
+	case Comment:
+		return "comment"
+	case Blank:
+		return "blank"
+	}
+	return "TokenKind(" + strconv.Itoa(int(kind)) + ")"
+}
+
+/*
+Token is a single line of an ini file, as returned by Scanner.Next.
+
+Section names are returned as written between the brackets, so [server "http"] has the Section `server "http"`
+and [prod : base] has the Section `prod : base`. Keys keep the spelling used in the file.
+*/
+type Token struct {
+	Kind    TokenKind
+	Pos     Position
+	Text    string //the line with surrounding white space removed
+	Raw     string //the line as it was read, without the line ending
+	Section string //the section name for SectionStart
+	Key     string //the key for KeyValue, ArrayItem and MapItem, without the [] or [name]
+	Name    string //the entry name for MapItem
+	Value   string //the value with surrounding quotes removed, or the text after the # or ; for Comment
+}
+
+/*
+Scanner reads an ini file one line at a time, without storing the values it reads.
+It is the tokenizer used by LoadConfiguration, and can be used by tools that need comments, blank lines
+or positions, or that read files too large to keep in memory.
+*/
+type Scanner struct {
+	scanner *bufio.Scanner
+	start   startReader //the start of the input, read while looking for a byte order mark
+	file    string
+	line    int
+	err     error
+
+	invalidUTF8 UTF8Policy
+	encoding    textEncoding
+
+	//the current line, the slices are only valid until the next call to scan
+	kind  TokenKind
+	raw   []byte
+	text  []byte
+	key   []byte
+	name  []byte
+	value []byte
+
+	ending string //the current line's ending, "" for a last line without one
+}
+
+/*
+NewScanner returns a Scanner reading from input. Input is UTF-8, a byte order mark at the start is removed,
+and input that starts with a UTF-16 byte order mark is converted from UTF-16. Lines can end with \n, \r\n or \r.
+*/
+func NewScanner(input io.Reader) *Scanner {
+	//bufio.Scanner starts with a 4KB buffer, a reader that knows its length, like a strings.Reader, gets a smaller one
+	size := 4096
+
+	if sized, ok := input.(interface{ Len() int }); ok {
+		size = min(max(sized.Len()+1, 64), size)
+	}
+
+	s := new(Scanner)
+	text, encoding := decodeText(input, &s.start)
+
+	s.scanner = bufio.NewScanner(text)
+	s.scanner.Buffer(make([]byte, size), bufio.MaxScanTokenSize)
+	s.scanner.Split(scanLines)
+	s.encoding = encoding
+	return s
+}
+
+/*
+Next returns the next line of the input. At the end of the input it returns io.EOF.
+A line that isn't valid ini stops the scan and its error is returned from every later call.
+*/
+func (scanner *Scanner) Next() (Token, error) {
+	if !scanner.scan() {
+		return Token{}, scanner.err
+	}
+
+	token := Token{
+		Kind: scanner.kind,
+		Pos:  scanner.position(),
+		Text: string(scanner.text),
+		Raw:  string(scanner.raw),
+	}
+
+	switch scanner.kind {
+	case SectionStart:
+		token.Section = string(scanner.value)
+	case KeyValue, ArrayItem, MapItem:
+		token.Key = string(scanner.key)
+		token.Name = string(scanner.name)
+		token.Value = trimQuotes(string(scanner.value))
+	case Comment:
+		token.Value = string(scanner.value)
+	}
+
+	return token, nil
+}
+
+//Return the part of text, a copy of the end of the current line's trimmed text, that the slice part refers to
+func (scanner *Scanner) cut(text string, part []byte) string {
+	if len(part) == 0 {
+		return ""
+	}
+
+	start := cap(scanner.text) - cap(part) - (len(scanner.text) - len(text))
+	return text[start : start+len(part)]
+}
+
+//Return the position of the current line
+func (scanner *Scanner) position() Position {
+	return Position{File: scanner.file, Line: scanner.line}
+}
+
+//Return the current line's trimmed text from the start of part to the end
+func (scanner *Scanner) from(part []byte) []byte {
+	return scanner.text[cap(scanner.text)-cap(part):]
+}
+
+//Read the next line into the scanner, returning false at the end of the input or on an error
+func (scanner *Scanner) scan() bool {
+	if scanner.err != nil {
+		return false
+	}
+
+	if !scanner.scanner.Scan() {
+		scanner.err = scanner.scanner.Err()
+
+		if scanner.err == nil {
+			scanner.err = io.EOF
+		}
+
+		return false
+	}
+
+	scanner.line++
+	raw, ending := splitEnding(scanner.scanner.Bytes())
+	scanner.ending = ending
+	raw, err := scanner.checkUTF8(raw)
+
+	if err != nil {
+		scanner.err = err
+		return false
+	}
+
+	line := bytes.TrimSpace(raw)
+	scanner.raw = raw
+	scanner.text = line
+	scanner.key, scanner.name, scanner.value = nil, nil, nil
+
+	if len(line) == 0 {
+		scanner.kind = Blank
+		return true
+	}
+
+	if line[0] == ';' || line[0] == '#' {
+		scanner.kind = Comment
+		scanner.value = bytes.TrimSpace(line[1:])
+		return true
+	}
+
+	if line[0] == '[' {
+
+		if line[len(line)-1] != ']' {
+			scanner.err = fmt.Errorf("mini: section names must be surrounded by [ and ], as in [section], at %s", scanner.position())
+			return false
+		}
+
+		scanner.kind = SectionStart
+		scanner.value = bytes.TrimSpace(line[1 : len(line)-1])
+		return true
+	}
+
+	index := bytes.IndexByte(line, '=')
+
+	if index <= 0 {
+		scanner.err = fmt.Errorf("mini: configuration format requires an equals between the key and value at %s", scanner.position())
+		return false
+	}
+
+	key := bytes.TrimSpace(line[0:index])
+	scanner.kind = KeyValue
+
+	if len(key) >= 2 && key[len(key)-2] == '[' && key[len(key)-1] == ']' {
+		scanner.kind = ArrayItem
+		key = key[0 : len(key)-2]
+	} else if len(key) > 0 && key[len(key)-1] == ']' {
+		if open := bytes.IndexByte(key, '['); open > 0 {
+			scanner.kind = MapItem
+			scanner.name = key[open+1 : len(key)-1]
+			key = bytes.TrimSpace(key[0:open])
+		}
+	}
+
+	scanner.key = key
+	scanner.value = bytes.TrimSpace(line[index+1:])
+	return true
+}
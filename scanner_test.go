@@ -0,0 +1,83 @@
+package mini
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+const scannerIni = `; global
+name = "hello"
+
+[server "http"]
+hosts[] = a
+headers[Accept] = text/html
+  # indented`
+
+func TestScanner(t *testing.T) {
+
+	scanner := NewScanner(strings.NewReader(scannerIni))
+
+	var tokens []Token
+	for {
+		token, err := scanner.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err, "Scanner should read without error.")
+		tokens = append(tokens, token)
+	}
+
+	assert.Equal(t, len(tokens), 7, "Scanner should return a token for each line")
+	assert.Equal(t, tokens[0], Token{Kind: Comment, Pos: Position{Line: 1}, Text: "; global", Raw: "; global", Value: "global"}, "Read comment wrong")
+	assert.Equal(t, tokens[1], Token{Kind: KeyValue, Pos: Position{Line: 2}, Text: `name = "hello"`, Raw: `name = "hello"`, Key: "name", Value: "hello"}, "Read key wrong")
+	assert.Equal(t, tokens[2], Token{Kind: Blank, Pos: Position{Line: 3}}, "Read blank wrong")
+	assert.Equal(t, tokens[3].Kind, SectionStart, "Read section kind wrong")
+	assert.Equal(t, tokens[3].Section, `server "http"`, "Section name should be returned as written")
+	assert.Equal(t, tokens[4].Kind, ArrayItem, "Read array kind wrong")
+	assert.Equal(t, tokens[4].Key, "hosts", "Array key should not include []")
+	assert.Equal(t, tokens[5].Kind, MapItem, "Read map kind wrong")
+	assert.Equal(t, tokens[5].Key, "headers", "Map key should not include the name")
+	assert.Equal(t, tokens[5].Name, "Accept", "Read map name wrong")
+	assert.Equal(t, tokens[5].Value, "text/html", "Read map value wrong")
+	assert.Equal(t, tokens[6].Kind, Comment, "Indented comment should be a comment")
+	assert.Equal(t, tokens[6].Pos.Line, 7, "Read comment line wrong")
+	assert.Equal(t, tokens[6].Raw, "  # indented", "Raw should keep white space")
+}
+
+func TestScannerError(t *testing.T) {
+
+	scanner := NewScanner(strings.NewReader("a=1\n[broken\nb=2"))
+
+	_, err := scanner.Next()
+	assert.Nil(t, err, "First line should read without error.")
+
+	_, err = scanner.Next()
+	assert.NotNil(t, err, "Unclosed section should be an error.")
+	assert.Equal(t, err.Error(), "mini: section names must be surrounded by [ and ], as in [section], at line 2", "Error should include the line")
+
+	_, err = scanner.Next()
+	assert.NotNil(t, err, "Scanner should stop after an error.")
+
+	_, err = LoadConfigurationFromReader(strings.NewReader("a=1\n\nno equals"))
+	assert.Equal(t, err.Error(), "mini: configuration format requires an equals between the key and value at line 3", "Error should include the line")
+}
+
+func TestScannerTrimsSectionNames(t *testing.T) {
+
+	scanner := NewScanner(strings.NewReader("[ server ]"))
+
+	token, err := scanner.Next()
+	assert.Nil(t, err, "Section should read without error.")
+	assert.Equal(t, token.Section, "server", "Spaces around a section name should be trimmed")
+
+	config, err := LoadConfigurationFromReader(strings.NewReader("[ server ]\nport=80"))
+	assert.Nil(t, err, "Configuration should load without error.")
+	assert.Equal(t, config.IntegerFromSection("server", "port", 0), int64(80), "Section should be found without the spaces")
+}
+
+func TestTokenKindString(t *testing.T) {
+	assert.Equal(t, ArrayItem.String(), "array item", "Wrong name for ArrayItem")
+	assert.Equal(t, TokenKind(42).String(), "TokenKind(42)", "Wrong name for an unknown kind")
+}
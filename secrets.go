@@ -0,0 +1,232 @@
+package mini
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+/*
+Redacted replaces the text of secret values in String, ToMap, the encoder and error messages.
+*/
+const Redacted = "REDACTED"
+
+/*
+SecretResolver expands a reference to a secret, like secret://vault/db or file:///run/secrets/db,
+into the secret's text. Resolvers are set in Options.SecretResolvers by the scheme they handle.
+*/
+type SecretResolver interface {
+	ResolveSecret(ref *url.URL) (string, error)
+}
+
+/*
+FileResolver resolves file:// references by reading the file, a trailing line ending is removed.
+If Root is set, only files inside Root can be read. Symbolic links are followed before the path is checked,
+so a link inside Root can't be used to read a file outside it.
+*/
+type FileResolver struct {
+	Root string
+}
+
+func (resolver FileResolver) ResolveSecret(ref *url.URL) (string, error) {
+	if len(ref.Host) > 0 && ref.Host != "localhost" {
+		return "", fmt.Errorf("mini: file reference %q is on another host", ref.Redacted())
+	}
+
+	filePath := filepath.FromSlash(ref.Path)
+
+	if len(resolver.Root) > 0 {
+		root, err := filepath.EvalSymlinks(resolver.Root)
+
+		if err != nil {
+			return "", err
+		}
+
+		//read the file the links lead to, so the one checked is the one read
+		if filePath, err = filepath.EvalSymlinks(filePath); err != nil {
+			return "", err
+		}
+
+		rel, err := filepath.Rel(root, filePath)
+
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("mini: file reference %q is outside %s", ref.Redacted(), resolver.Root)
+		}
+	}
+
+	data, err := os.ReadFile(filePath)
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+}
+
+//A value that refers to a secret, resolved the first time it's read
+type secretRef struct {
+	url      *url.URL
+	resolver SecretResolver
+	err      error                 //set on the value left in place of a reference that couldn't be resolved
+	resolved atomic.Pointer[value] //the secret, or the failed value, once the reference is resolved
+}
+
+//Return the resolver for a value written as a reference, or nil
+func (options *Options) secretRef(raw string) *secretRef {
+	scheme, _, ok := strings.Cut(raw, "://")
+
+	if !ok || len(scheme) == 0 {
+		return nil
+	}
+
+	resolver, ok := options.SecretResolvers[strings.ToLower(scheme)]
+
+	if !ok {
+		return nil
+	}
+
+	ref, err := url.Parse(raw)
+
+	if err != nil {
+		return nil
+	}
+
+	return &secretRef{url: ref, resolver: resolver}
+}
+
+/*
+Return the value a reference refers to, other values are returned as they are. The resolver is only asked once,
+its result, or its error, is kept for later reads.
+*/
+func (v *value) resolve() *value {
+	if v == nil || v.more == nil || v.more.ref == nil || v.more.ref.err != nil {
+		return v
+	}
+
+	ref := v.more.ref
+
+	if resolved := ref.resolved.Load(); resolved != nil {
+		return resolved
+	}
+
+	text, err := ref.resolver.ResolveSecret(ref.url)
+
+	if err != nil {
+		err = fmt.Errorf("mini: can't resolve %s at %s: %w", ref.url.Redacted(), v.position(), err)
+		failed := &value{raw: v.raw, src: v.src, line: v.line, more: &composite{secret: true, ref: &secretRef{url: ref.url, err: err}}}
+		ref.resolved.Store(failed)
+		return failed
+	}
+
+	//the secret is never a reference itself
+	options := *v.src.options
+	options.SecretResolvers = nil
+
+	resolved := newValue(escapeText(text), int(v.line), &source{file: v.src.file, options: &options})
+	resolved.markSecret()
+	ref.resolved.Store(resolved)
+	return resolved
+}
+
+//Resolve the references in a list of values, the list is only copied if it has any
+func resolveAll(list []*value) []*value {
+	for i, v := range list {
+		if v.more != nil && v.more.ref != nil {
+			resolved := make([]*value, len(list))
+			copy(resolved, list[:i])
+
+			for j := i; j < len(list); j++ {
+				resolved[j] = list[j].resolve()
+			}
+
+			return resolved
+		}
+	}
+	return list
+}
+
+//Mark a value, and its entries, as secret
+func (v *value) markSecret() {
+	more := v.extra()
+	more.secret = true
+
+	for _, entry := range more.entries {
+		entry.markSecret()
+	}
+}
+
+//Return true if the key matches one of the SecretKeys patterns
+func (options *Options) isSecret(sectionName string, key string) bool {
+	key = strings.ToLower(key)
+	full := key
+
+	if len(sectionName) > 0 {
+		full = strings.ToLower(sectionName) + "." + key
+	}
+
+	for _, pattern := range options.SecretKeys {
+		pattern = strings.ToLower(pattern)
+
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+
+		if ok, _ := path.Match(pattern, full); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Return the text to show for a value in an error message
+func (v *value) displayText() string {
+	if v.isSecret() {
+		return Redacted
+	}
+	return v.raw
+}
+
+/*
+Secret returns true if the value is a secret, because its key matches Options.SecretKeys or
+it was written as a reference to a secret.
+*/
+func (val Value) Secret() bool {
+	return val.v != nil && val.v.isSecret()
+}
+
+//Returned in place of errors that quote a secret value
+var errInvalidSecret = errors.New("mini: secret value can't be converted")
+
+//Remove the text of a secret value from a conversion error
+func (v *value) redactError(err error) error {
+	if !v.isSecret() {
+		return err
+	}
+	return redactSecret(err, v.raw)
+}
+
+//Remove raw from err, errors that don't include it are returned as they are
+func redactSecret(err error, raw string) error {
+	if err == nil {
+		return nil
+	}
+
+	var numError *strconv.NumError
+
+	if errors.As(err, &numError) {
+		return &strconv.NumError{Func: numError.Func, Num: Redacted, Err: numError.Err}
+	}
+
+	if len(raw) > 0 && strings.Contains(err.Error(), raw) {
+		return errInvalidSecret
+	}
+
+	return err
+}
@@ -0,0 +1,191 @@
+package mini
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadSecrets(t *testing.T, ini string, options Options) *Config {
+	config := &Config{Options: options}
+	err := config.InitializeFromReader(strings.NewReader(ini))
+
+	assert.Nil(t, err, "Simple configuration should load without error.")
+	return config
+}
+
+func TestSecretKeys(t *testing.T) {
+
+	ini := `
+password=hunter2
+user=admin
+[db]
+Token=abc
+tokens[]=1
+tokens[]=2
+[cache]
+token=def
+`
+	config := loadSecrets(t, ini, Options{SecretKeys: []string{"password", "db.*token*"}})
+
+	password, _ := config.Lookup("", "password")
+	assert.True(t, password.Secret(), "Key matching a pattern should be secret")
+	assert.Equal(t, password.String(), Redacted, "String should redact secrets")
+	assert.Equal(t, config.String("password", ""), "hunter2", "Getters should return the secret")
+
+	token, _ := config.Lookup("db", "token")
+	assert.True(t, token.Secret(), "Section pattern should match without regard to case")
+
+	tokens, _ := config.Lookup("db", "tokens")
+	assert.Equal(t, tokens.String(), Redacted, "Arrays should be redacted")
+	assert.True(t, tokens.Index(1).Secret(), "Array entries should be secret")
+
+	other, _ := config.Lookup("cache", "token")
+	assert.False(t, other.Secret(), "Pattern should only match its section")
+
+	m, err := config.ToMap()
+	assert.Nil(t, err, "ToMap should not return an error.")
+	assert.Equal(t, m["password"], Redacted, "ToMap should redact secrets")
+	assert.Equal(t, m["user"], "admin", "ToMap should keep other values")
+	assert.Equal(t, m["db"].(map[string]interface{})["tokens"], []string{Redacted, Redacted}, "ToMap should redact array entries")
+}
+
+func TestSecretErrors(t *testing.T) {
+
+	config := loadSecrets(t, "port=s3cret\n", Options{SecretKeys: []string{"port"}})
+
+	_, err := config.LookupDuration("", "port")
+	var valueErr *ValueError
+
+	assert.True(t, errors.As(err, &valueErr), "Bad secret should return a ValueError")
+	assert.Equal(t, valueErr.Value, Redacted, "ValueError should redact the value")
+	assert.False(t, strings.Contains(err.Error(), "s3cret"), "Error should not contain the secret: "+err.Error())
+
+	val, _ := config.Lookup("", "port")
+	_, err = val.Int()
+	assert.False(t, strings.Contains(err.Error(), "s3cret"), "Value error should not contain the secret: "+err.Error())
+}
+
+func TestFileResolver(t *testing.T) {
+
+	dir := t.TempDir()
+	secretPath := filepath.Join(dir, "db")
+	assert.Nil(t, os.WriteFile(secretPath, []byte("p\"ss\n"), 0600), "Secret file should be written")
+
+	ini := "password=file://" + filepath.ToSlash(secretPath) + "\n" +
+		"missing=file://" + filepath.ToSlash(filepath.Join(dir, "missing")) + "\n" +
+		"url=http://example.com\n"
+
+	config := loadSecrets(t, ini, Options{SecretResolvers: map[string]SecretResolver{"file": FileResolver{Root: dir}}})
+
+	assert.Equal(t, config.String("password", ""), "p\"ss", "Reference should be resolved without its line ending")
+	assert.Equal(t, config.String("url", ""), "http://example.com", "Schemes without a resolver should be left alone")
+
+	val, _ := config.Lookup("", "password")
+	assert.True(t, val.Secret(), "References should be secret")
+	assert.Equal(t, val.String(), Redacted, "References should be redacted")
+	assert.Equal(t, val.Raw(), "file://"+filepath.ToSlash(secretPath), "Raw should return the reference")
+
+	assert.Nil(t, os.WriteFile(secretPath, []byte("changed"), 0600), "Secret file should be written")
+	assert.Equal(t, config.String("password", ""), "p\"ss", "References should be resolved once and kept")
+
+	missing, _ := config.Lookup("", "missing")
+	_, err := missing.Text()
+	assert.True(t, errors.Is(err, os.ErrNotExist), "Unresolved reference should return the resolver's error")
+
+	outside := loadSecrets(t, ini, Options{SecretResolvers: map[string]SecretResolver{"file": FileResolver{Root: filepath.Join(dir, "sub")}}})
+	_, err = outside.LookupURL("", "password")
+	assert.NotNil(t, err, "Files outside Root should not be read")
+
+	root := filepath.Join(dir, "root")
+	assert.Nil(t, os.Mkdir(root, 0700), "Root should be made")
+	link := filepath.Join(root, "link")
+
+	if err := os.Symlink(secretPath, link); err != nil {
+		t.Skip("Symbolic links aren't supported: " + err.Error())
+	}
+
+	linked := loadSecrets(t, "password=file://"+filepath.ToSlash(link)+"\n", Options{SecretResolvers: map[string]SecretResolver{"file": FileResolver{Root: root}}})
+	_, err = linked.LookupURL("", "password")
+	assert.NotNil(t, err, "A link inside Root should not read a file outside it")
+
+	linkedRoot := filepath.Join(dir, "linked")
+	assert.Nil(t, os.Symlink(root, linkedRoot), "Link to Root should be made")
+	assert.Nil(t, os.WriteFile(filepath.Join(root, "inside"), []byte("in"), 0600), "Secret file should be written")
+
+	inside := loadSecrets(t, "password=file://"+filepath.ToSlash(filepath.Join(linkedRoot, "inside"))+"\n", Options{SecretResolvers: map[string]SecretResolver{"file": FileResolver{Root: root}}})
+	assert.Equal(t, inside.String("password", ""), "in", "Files inside Root should be read through a link to Root")
+}
+
+type countingResolver struct {
+	calls *int
+}
+
+func (resolver countingResolver) ResolveSecret(ref *url.URL) (string, error) {
+	*resolver.calls++
+
+	if ref.Host == "missing" {
+		return "", os.ErrNotExist
+	}
+	return "s3cret", nil
+}
+
+func TestSecretResolvedOnce(t *testing.T) {
+
+	calls := 0
+	config := loadSecrets(t, "token=vault://db\nmissing=vault://missing\n", Options{SecretResolvers: map[string]SecretResolver{"vault": countingResolver{&calls}}})
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, config.String("token", ""), "s3cret", "Reference should be resolved")
+		missing, _ := config.Lookup("", "missing")
+		_, err := missing.Text()
+		assert.True(t, errors.Is(err, os.ErrNotExist), "Unresolved reference should keep the resolver's error")
+	}
+
+	assert.Equal(t, calls, 2, "Each reference should be resolved once")
+}
+
+type secretSettings struct {
+	User     string
+	Password string `ini:"password,secret"`
+	Port     int    `ini:",secret"`
+}
+
+func TestSecretTag(t *testing.T) {
+
+	var settings secretSettings
+	err := Unmarshal([]byte("user=admin\npassword=hunter2\nport=s3cret"), &settings)
+
+	var typeErr *UnmarshalTypeError
+	assert.True(t, errors.As(err, &typeErr), "Bad secret should return an UnmarshalTypeError")
+	assert.Equal(t, typeErr.Value, Redacted, "UnmarshalTypeError should redact the value")
+	assert.False(t, strings.Contains(err.Error(), "s3cret"), "Error should not contain the secret: "+err.Error())
+	assert.Equal(t, settings.Password, "hunter2", "Secret fields should be decoded")
+
+	var buf bytes.Buffer
+	encoder := NewEncoder(&buf)
+	encoder.RedactSecrets()
+
+	assert.Nil(t, encoder.Encode(settings), "Encode should not return an error.")
+	assert.Equal(t, buf.String(), "User=admin\npassword=REDACTED\nPort=REDACTED\n", "Secret fields should be redacted")
+
+	data, _ := Marshal(settings)
+	assert.Equal(t, string(data), "User=admin\npassword=hunter2\nPort=0\n", "Marshal should not redact by default")
+}
+
+func TestDocumentRedact(t *testing.T) {
+
+	doc, err := ParseDocument(strings.NewReader("user=admin\n[db]\n  password = \"hunter2\" \nkeys[a]=1\n"))
+	assert.Nil(t, err, "ParseDocument should not return an error.")
+
+	doc.Redact([]string{"password", "db.keys"})
+
+	var buf bytes.Buffer
+	doc.WriteTo(&buf)
+	assert.Equal(t, buf.String(), "user=admin\n[db]\n  password = REDACTED\nkeys[a]=REDACTED\n", "Secret values should be redacted in place")
+}
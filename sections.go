@@ -0,0 +1,283 @@
+package mini
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//The key used inside a section to name the section it extends
+const extendsKey = "extends"
+
+/*
+Return the name of the section in a header and the name of the section it extends. With extends set, as by
+Options.Extends, [prod : base] is the section prod extending base, otherwise the colon is part of the name.
+*/
+func splitSectionName(header string, extends bool) (string, string) {
+	base := ""
+
+	if extends {
+		if colon := strings.IndexByte(header, ':'); colon >= 0 {
+			base = parseSectionName(strings.TrimSpace(header[colon+1:]))
+			header = strings.TrimSpace(header[:colon])
+		}
+	}
+
+	return parseSectionName(header), base
+}
+
+//Turn a git style section header, server "http", into the dotted form, server.http
+func parseSectionName(name string) string {
+	quote := strings.IndexByte(name, '"')
+
+	if quote <= 0 || !strings.HasSuffix(name, "\"") {
+		return name
+	}
+
+	subsection, err := strconv.Unquote(name[quote:])
+
+	if err != nil {
+		return name
+	}
+
+	return strings.TrimSpace(name[:quote]) + "." + subsection
+}
+
+//Return the remainder of name after prefix, compared using the rules for section names
+func (options *Options) trimSectionPrefix(name string, prefix string) (string, bool) {
+	if !options.CaseInsensitiveSections {
+		if len(name) <= len(prefix) || !strings.HasPrefix(name, prefix) {
+			return "", false
+		}
+		return name[len(prefix):], true
+	}
+
+	//walk both names a rune at a time, since folding case can change the length of a rune
+	rest := name
+
+	for _, r := range prefix {
+		c, size := utf8.DecodeRuneInString(rest)
+
+		if size == 0 || !equalFoldRune(c, r) {
+			return "", false
+		}
+		rest = rest[size:]
+	}
+
+	if len(rest) == 0 {
+		return "", false
+	}
+
+	return rest, true
+}
+
+//Check if two runes are the same without regard to case, using the same folding as strings.EqualFold
+func equalFoldRune(a rune, b rune) bool {
+	if a == b {
+		return true
+	}
+
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Return the closest existing section above sectionName, or the global values
+func (config *Config) ancestor(sectionName string) *configSection {
+	for i := strings.LastIndex(sectionName, "."); i > 0; i = strings.LastIndex(sectionName, ".") {
+		sectionName = sectionName[:i]
+
+		if section, ok := config.sections[config.Options.sectionKey(sectionName)]; ok {
+			return section
+		}
+	}
+
+	return &(config.configSection)
+}
+
+//Point each section at the sections it inherits values from
+func (config *Config) linkSections() error {
+	for _, section := range config.sectionOrder {
+		if config.Options.InheritValues {
+			section.parent = config.ancestor(section.name)
+		}
+
+		if len(section.extendsName) > 0 {
+			base, ok := config.sections[config.Options.sectionKey(section.extendsName)]
+
+			if !ok {
+				return fmt.Errorf("mini: section %s extends missing section %s", section.name, section.extendsName)
+			}
+
+			section.extends = base
+		}
+	}
+
+	if !config.Options.Extends {
+		return nil //parents alone can't form a cycle
+	}
+
+	state := make(map[*configSection]int)
+
+	for _, section := range config.sectionOrder {
+		if cycle := findCycle(section, state); cycle != nil {
+			return fmt.Errorf("mini: section %s inherits from itself", cycle.name)
+		}
+	}
+
+	return nil
+}
+
+//Return a section that can be reached from itself through extends, or nil. Lookups don't search the parents of an
+//extended section, so parents can't be part of a cycle
+func findCycle(section *configSection, state map[*configSection]int) *configSection {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	if section == nil || state[section] == visited {
+		return nil
+	}
+
+	if state[section] == visiting {
+		return section
+	}
+
+	state[section] = visiting
+
+	if cycle := findCycle(section.extends, state); cycle != nil {
+		return cycle
+	}
+
+	state[section] = visited
+	return nil
+}
+
+//Gather the keys of a section and every section it inherits from, keeping the first spelling of each key
+func (section *configSection) collectKeys(keys map[string]string) {
+	for ; section != nil; section = section.parent {
+		for extended := section; extended != nil; extended = extended.extends {
+			for _, key := range extended.order {
+				lower := strings.ToLower(key)
+
+				if _, ok := keys[lower]; !ok {
+					keys[lower] = key
+				}
+			}
+		}
+	}
+}
+
+/*
+AllKeysForSection returns the keys found in the section named sectionName along with the keys it inherits
+through Options.Extends and Options.InheritValues. Keys are sorted and returned as they were first spelled in the file.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) AllKeysForSection(sectionName string) []string {
+	section := config.sectionForName(sectionName)
+
+	if section == nil {
+		return nil
+	}
+
+	names := make(map[string]string)
+	section.collectKeys(names)
+
+	lowerKeys := make([]string, 0, len(names))
+	for key := range names {
+		lowerKeys = append(lowerKeys, key)
+	}
+	sort.Strings(lowerKeys)
+
+	keys := make([]string, len(lowerKeys))
+	for i, key := range lowerKeys {
+		keys[i] = names[key]
+	}
+	return keys
+}
+
+/*
+ChildSections returns the names of the sections directly below the section named sectionName, in the order
+they first appeared in the file. [server.http] and [server "http"] are both children of [server]. The section
+itself doesn't need to exist in the file for its children to be found.
+
+If the section name matches the config.name or "" the top level sections, those without a dot, are returned.
+*/
+func (config *Config) ChildSections(sectionName string) []string {
+	children := make([]string, 0)
+	isGlobal := len(sectionName) == 0 || config.Options.sameSection(sectionName, config.name)
+
+	for _, section := range config.sectionOrder {
+		childName := section.name
+
+		if !isGlobal {
+			var ok bool
+			childName, ok = config.Options.trimSectionPrefix(section.name, sectionName+".")
+
+			if !ok {
+				continue
+			}
+		}
+
+		if !strings.Contains(childName, ".") {
+			children = append(children, section.name)
+		}
+	}
+
+	return children
+}
+
+/*
+Sub returns a view of the config scoped to the section named sectionName. The section's values become the
+global values of the view, and its children become the view's sections, so Sub("server") finds [server.http]
+as "http". The view shares its data with the config.
+
+If there is no such section the view is empty, other than any children the section has. If the section name
+matches the config.name or "" the config itself is returned.
+*/
+func (config *Config) Sub(sectionName string) *Config {
+	section := config.sectionForName(sectionName)
+
+	if section == &(config.configSection) {
+		return config
+	}
+
+	sub := new(Config)
+	sub.Options = config.Options
+	sub.sections = make(map[string]*configSection)
+
+	if section != nil {
+		sub.configSection = *section
+		sub.name = ""
+	} else {
+		sub.configSection.init("")
+
+		if config.Options.InheritValues {
+			sub.parent = config.ancestor(sectionName)
+		}
+	}
+
+	for _, child := range config.sectionOrder {
+		childName, ok := config.Options.trimSectionPrefix(child.name, sectionName+".")
+
+		if !ok {
+			continue
+		}
+
+		scoped := *child
+		scoped.name = childName
+		sub.sections[sub.Options.sectionKey(childName)] = &scoped
+		sub.sectionOrder = append(sub.sectionOrder, &scoped)
+	}
+
+	return sub
+}
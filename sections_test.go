@@ -0,0 +1,234 @@
+package mini
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const nestedIni = `port=80
+timeout=30
+
+[server]
+host=example.com
+port=8080
+
+[server.http]
+port=8081
+
+[server "https"]
+port=8443
+cert=server.pem
+
+[server.https.legacy]
+cipher=rc4
+
+[client.http]
+retries=3`
+
+func TestGitStyleSectionName(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(nestedIni))
+
+	assert.Nil(t, err, "Nested configuration should load without error.")
+
+	assert.Equal(t, config.IntegerFromSection("server.https", "port", 0), int64(8443), "Read value of port wrong")
+	assert.Equal(t, config.StringFromSection("server.https", "cert", ""), "server.pem", "Read value of cert wrong")
+	assert.Equal(t, config.SectionsInOrder(), []string{"server", "server.http", "server.https", "server.https.legacy", "client.http"}, "Read section names wrong")
+}
+
+func TestChildSections(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(nestedIni))
+
+	assert.Nil(t, err, "Nested configuration should load without error.")
+
+	assert.Equal(t, config.ChildSections("server"), []string{"server.http", "server.https"}, "Read children of server wrong")
+	assert.Equal(t, config.ChildSections("server.https"), []string{"server.https.legacy"}, "Read children of server.https wrong")
+	assert.Equal(t, config.ChildSections("client"), []string{"client.http"}, "Missing sections can still have children")
+	assert.Equal(t, config.ChildSections(""), []string{"server"}, "Read top level sections wrong")
+	assert.Equal(t, len(config.ChildSections("server.http")), 0, "server.http has no children")
+}
+
+func TestChildSectionsFoldCase(t *testing.T) {
+
+	//the Kelvin sign is three bytes long and folds to the one byte k
+	ini := "[\u212Aitchen.sink]\ntap=hot\n[Kitchen.oven]\ntemp=200"
+
+	config := &Config{Options: Options{CaseInsensitiveSections: true}}
+	err := config.InitializeFromReader(strings.NewReader(ini))
+
+	assert.Nil(t, err, "Nested configuration should load without error.")
+
+	assert.Equal(t, config.ChildSections("kitchen"), []string{"\u212Aitchen.sink", "Kitchen.oven"}, "Children should be found when case folding changes the length")
+	assert.Equal(t, config.Sub("KITCHEN").StringFromSection("sink", "tap", ""), "hot", "Sub should trim the folded prefix")
+
+	exact, err := LoadConfigurationFromReader(strings.NewReader(ini))
+
+	assert.Nil(t, err, "Nested configuration should load without error.")
+	assert.Equal(t, exact.ChildSections("Kitchen"), []string{"Kitchen.oven"}, "Children should be matched exactly by default")
+}
+
+func TestSub(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(nestedIni))
+
+	assert.Nil(t, err, "Nested configuration should load without error.")
+
+	server := config.Sub("server")
+
+	assert.Equal(t, server.String("host", ""), "example.com", "Read value of host wrong")
+	assert.Equal(t, server.IntegerFromSection("http", "port", 0), int64(8081), "Read value of port wrong")
+	assert.Equal(t, server.StringFromSection("https.legacy", "cipher", ""), "rc4", "Read value of cipher wrong")
+	assert.Equal(t, server.SectionNames(), []string{"http", "https", "https.legacy"}, "Read section names of view wrong")
+	assert.Equal(t, server.Integer("timeout", 0), int64(0), "Views don't inherit by default")
+
+	legacy := config.Sub("server").Sub("https").Sub("legacy")
+	assert.Equal(t, legacy.String("cipher", ""), "rc4", "Read value of cipher wrong")
+
+	client := config.Sub("client")
+	assert.Equal(t, len(client.Keys()), 0, "Missing section should have no keys")
+	assert.Equal(t, client.IntegerFromSection("http", "retries", 0), int64(3), "Read value of retries wrong")
+
+	assert.Equal(t, len(config.Sub("missing").SectionNames()), 0, "Missing section should be empty")
+	assert.True(t, config.Sub("") == config, "Global view should be the config")
+}
+
+func TestInheritValues(t *testing.T) {
+
+	config := &Config{Options: Options{InheritValues: true}}
+	err := config.InitializeFromReader(strings.NewReader(nestedIni))
+
+	assert.Nil(t, err, "Nested configuration should load without error.")
+
+	assert.Equal(t, config.IntegerFromSection("server.http", "port", 0), int64(8081), "Own values should win")
+	assert.Equal(t, config.StringFromSection("server.http", "host", ""), "example.com", "Missing key should come from parent")
+	assert.Equal(t, config.IntegerFromSection("server.http", "timeout", 0), int64(30), "Missing key should come from globals")
+	assert.Equal(t, config.IntegerFromSection("server.https.legacy", "port", 0), int64(8443), "Missing key should come from closest parent")
+	assert.Equal(t, config.IntegerFromSection("client.http", "port", 0), int64(80), "Missing parent should be skipped")
+	assert.Equal(t, config.StringFromSection("server.http", "missing", "def"), "def", "Missing everywhere should be the default")
+
+	assert.Equal(t, config.Sub("server").Integer("timeout", 0), int64(30), "Views should inherit from globals")
+	assert.Equal(t, config.Sub("client").Integer("timeout", 0), int64(30), "Empty views should inherit from globals")
+	assert.Equal(t, len(config.KeysForSection("server.http")), 1, "Inherited keys aren't listed")
+}
+
+const extendsIni = `[base]
+host=db.internal
+port=5432
+pool=10
+
+[staging : base]
+host=staging.db
+
+[prod]
+extends=staging
+pool=50
+Replicas[]=a
+replicas[]=b`
+
+func TestExtends(t *testing.T) {
+
+	config := &Config{Options: Options{Extends: true}}
+	err := config.InitializeFromReader(strings.NewReader(extendsIni))
+
+	assert.Nil(t, err, "Extended configuration should load without error.")
+
+	assert.Equal(t, config.StringFromSection("staging", "host", ""), "staging.db", "Own values should win")
+	assert.Equal(t, config.IntegerFromSection("staging", "port", 0), int64(5432), "Missing key should come from base")
+	assert.Equal(t, config.IntegerFromSection("prod", "pool", 0), int64(50), "Own values should win")
+	assert.Equal(t, config.StringFromSection("prod", "host", ""), "staging.db", "Missing key should come from staging")
+	assert.Equal(t, config.IntegerFromSection("prod", "port", 0), int64(5432), "Missing key should come from base through staging")
+	assert.Equal(t, config.StringFromSection("prod", "extends", "none"), "none", "extends isn't a value")
+
+	assert.Equal(t, config.KeysForSection("prod"), []string{"pool", "Replicas"}, "Only own keys are listed")
+	assert.Equal(t, config.AllKeysForSection("prod"), []string{"host", "pool", "port", "Replicas"}, "Inherited keys should be listed")
+	assert.Nil(t, config.AllKeysForSection("missing"), "missing section should have no keys")
+}
+
+func TestExtendsOff(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(extendsIni))
+
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	assert.Equal(t, config.StringFromSection("staging : base", "host", ""), "staging.db", "Without Extends the colon is part of the name")
+	assert.Equal(t, config.StringFromSection("prod", "extends", ""), "staging", "Without Extends extends is a value")
+	assert.Equal(t, config.IntegerFromSection("prod", "port", 0), int64(0), "Without Extends nothing is inherited")
+}
+
+func TestExtendsWithInheritValues(t *testing.T) {
+
+	simpleIni := `timeout=30
+[base]
+port=5432
+[prod.db : base]
+host=prod.db`
+
+	config := &Config{Options: Options{Extends: true, InheritValues: true}}
+	err := config.InitializeFromReader(strings.NewReader(simpleIni))
+
+	assert.Nil(t, err, "Extended configuration should load without error.")
+
+	assert.Equal(t, config.IntegerFromSection("prod.db", "port", 0), int64(5432), "Missing key should come from base")
+	assert.Equal(t, config.IntegerFromSection("prod.db", "timeout", 0), int64(30), "Missing key should come from globals")
+	assert.Equal(t, config.AllKeysForSection("prod.db"), []string{"host", "port", "timeout"}, "Inherited keys should be listed")
+}
+
+func TestExtendsLookupOrder(t *testing.T) {
+
+	simpleIni := `timeout=30
+retries=1
+[templates]
+retries=5
+[templates.db]
+port=5432
+[prod]
+timeout=10
+[prod.db : templates.db]
+host=prod.db`
+
+	config := &Config{Options: Options{Extends: true, InheritValues: true}}
+	err := config.InitializeFromReader(strings.NewReader(simpleIni))
+
+	assert.Nil(t, err, "Extended configuration should load without error.")
+
+	assert.Equal(t, config.StringFromSection("prod.db", "host", ""), "prod.db", "Section should be searched first")
+	assert.Equal(t, config.IntegerFromSection("prod.db", "port", 0), int64(5432), "Extended section should be searched before the parent")
+	assert.Equal(t, config.IntegerFromSection("prod.db", "timeout", 0), int64(10), "Parent should be searched before the globals")
+	assert.Equal(t, config.IntegerFromSection("prod.db", "retries", 0), int64(1), "Parents of the extended section should not be searched")
+
+	origin, _ := config.Origin("prod.db", "timeout")
+	assert.Equal(t, origin.Section, "prod", "Origin should name the parent")
+}
+
+func TestExtendsCycle(t *testing.T) {
+
+	simpleIni := `[a : c]
+[b : a]
+[c]
+extends=b`
+
+	config := &Config{Options: Options{Extends: true}}
+	err := config.InitializeFromReader(strings.NewReader(simpleIni))
+
+	assert.NotNil(t, err, "Cycles should be an error.")
+
+	for _, ini := range []string{"[a : a.b]\n[a.b]", "[a.x]\nport=1\n[a : a.x]"} {
+		config = &Config{Options: Options{Extends: true, InheritValues: true}}
+		err = config.InitializeFromReader(strings.NewReader(ini))
+
+		assert.Nil(t, err, "A section extending its own child isn't a cycle: "+ini)
+	}
+
+	assert.Equal(t, config.IntegerFromSection("a.x", "port", 0), int64(1), "Child should keep its own value")
+	assert.Equal(t, config.IntegerFromSection("a", "port", 0), int64(1), "Section should find the value through extends")
+}
+
+func TestExtendsMissing(t *testing.T) {
+
+	config := &Config{Options: Options{Extends: true}}
+	err := config.InitializeFromReader(strings.NewReader(`[prod : base]`))
+
+	assert.NotNil(t, err, "Extending a missing section should be an error.")
+}
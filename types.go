@@ -0,0 +1,585 @@
+package mini
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+/*
+ErrNotFound is returned, inside a ValueError, by the Lookup functions when a key, or its section, is missing.
+Single values that are stored as arrays, and the reverse, are also reported as not found.
+*/
+var ErrNotFound = errors.New("mini: value not found")
+
+/*
+ValueError describes a value that is missing or can't be converted to the requested type.
+*/
+type ValueError struct {
+	Section string //the section searched, "" for the global values
+	Key     string
+	Value   string //the text that couldn't be converted, empty if the value is missing
+	Err     error  //ErrNotFound or the error from the conversion
+}
+
+func (e *ValueError) Error() string {
+	where := strconv.Quote(e.Key)
+
+	if len(e.Section) > 0 {
+		where += " in section " + strconv.Quote(e.Section)
+	}
+
+	if errors.Is(e.Err, ErrNotFound) {
+		return "mini: key " + where + " not found"
+	}
+
+	return "mini: key " + where + ": " + e.Err.Error()
+}
+
+/*
+Unwrap returns the underlying error, so errors.Is(err, ErrNotFound) can be used to test for missing values.
+*/
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+//Find a non-array value and convert it with parse
+func lookupValue[T any](section *configSection, sectionName string, key string, parse func(string) (T, error)) (T, error) {
+	var retVal T
+
+	val := get(section, key)
+
+	if val == nil {
+		return retVal, &ValueError{Section: sectionName, Key: key, Err: ErrNotFound}
+	}
+
+	if err := val.failed(); err != nil {
+		return retVal, &ValueError{Section: sectionName, Key: key, Value: Redacted, Err: err}
+	}
+
+	retVal, err := parse(val.raw)
+
+	if err != nil {
+		return retVal, &ValueError{Section: sectionName, Key: key, Value: val.displayText(), Err: val.redactError(err)}
+	}
+
+	return retVal, nil
+}
+
+//Find an array value and convert each entry with parse
+func lookupValues[T any](section *configSection, sectionName string, key string, parse func(string) (T, error)) ([]T, error) {
+	return parseValues(getArray(section, key), sectionName, key, parse)
+}
+
+//Convert each entry in an array value with parse
+func parseValues[T any](val []*value, sectionName string, key string, parse func(string) (T, error)) ([]T, error) {
+	if val == nil {
+		return nil, &ValueError{Section: sectionName, Key: key, Err: ErrNotFound}
+	}
+
+	retVal := make([]T, len(val))
+
+	for i, v := range val {
+		if err := v.failed(); err != nil {
+			return nil, &ValueError{Section: sectionName, Key: key, Value: Redacted, Err: err}
+		}
+
+		parsed, err := parse(v.raw)
+
+		if err != nil {
+			return nil, &ValueError{Section: sectionName, Key: key, Value: v.displayText(), Err: v.redactError(err)}
+		}
+
+		retVal[i] = parsed
+	}
+
+	return retVal, nil
+}
+
+func parseUint(str string) (uint64, error) {
+	return strconv.ParseUint(str, 0, 64)
+}
+
+func parseTimeLayout(layout string) func(string) (time.Time, error) {
+	if len(layout) == 0 {
+		layout = time.RFC3339
+	}
+
+	return func(str string) (time.Time, error) {
+		return time.Parse(layout, str)
+	}
+}
+
+func parseURL(str string) (*url.URL, error) {
+	if len(str) == 0 {
+		return nil, errors.New("empty url")
+	}
+	return url.Parse(str)
+}
+
+var sizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+	"pi":  1 << 50,
+	"pib": 1 << 50,
+}
+
+/*
+Parse a byte size such as 512, 10MB or 1.5GiB. Decimal units, KB, MB, GB, TB and PB, are powers of 1000
+and binary units, KiB, MiB, GiB, TiB and PiB, are powers of 1024. Units are case insensitive.
+*/
+func parseSize(str string) (int64, error) {
+	unit := strings.TrimLeftFunc(str, func(r rune) bool {
+		return unicode.IsDigit(r) || r == '.'
+	})
+	number := strings.TrimSpace(str[:len(str)-len(unit)])
+	unit = strings.ToLower(strings.TrimSpace(unit))
+
+	multiplier, ok := sizeUnits[unit]
+
+	if len(number) == 0 || !ok {
+		return 0, fmt.Errorf("invalid size %q", str)
+	}
+
+	size, err := strconv.ParseFloat(number, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", str)
+	}
+
+	size *= multiplier
+
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q is too large", str)
+	}
+
+	return int64(size), nil
+}
+
+/*
+Duration looks for the specified key and returns it as a time.Duration, using the format of time.ParseDuration.
+If not found the default value def is returned.
+*/
+func (config *Config) Duration(key string, def time.Duration) time.Duration {
+	return config.DurationFromSection("", key, def)
+}
+
+/*
+DurationFromSection looks for the specified key and returns it as a time.Duration. If not found the default value def is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) DurationFromSection(sectionName string, key string, def time.Duration) time.Duration {
+	val, err := config.LookupDuration(sectionName, key)
+
+	if err != nil {
+		return def
+	}
+	return val
+}
+
+/*
+Durations looks for an array of durations under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) Durations(key string) []time.Duration {
+	return config.DurationsFromSection("", key)
+}
+
+/*
+DurationsFromSection looks for an array of durations in the provided section and under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) DurationsFromSection(sectionName string, key string) []time.Duration {
+	val, _ := config.LookupDurations(sectionName, key)
+	return val
+}
+
+/*
+LookupDuration looks for the specified key and returns it as a time.Duration. If the key is missing, or can't be
+parsed, a *ValueError is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) LookupDuration(sectionName string, key string) (time.Duration, error) {
+	return lookupValue(config.sectionForName(sectionName), sectionName, key, time.ParseDuration)
+}
+
+/*
+LookupDurations looks for an array of durations in the provided section and under the provided key. If the key
+is missing, or any of the values can't be parsed, a *ValueError is returned.
+*/
+func (config *Config) LookupDurations(sectionName string, key string) ([]time.Duration, error) {
+	return lookupValues(config.sectionForName(sectionName), sectionName, key, time.ParseDuration)
+}
+
+/*
+Time looks for the specified key and returns it as a time.Time, parsed with layout as in time.Parse.
+If layout is "" time.RFC3339 is used. If not found the default value def is returned.
+*/
+func (config *Config) Time(key string, layout string, def time.Time) time.Time {
+	return config.TimeFromSection("", key, layout, def)
+}
+
+/*
+TimeFromSection looks for the specified key and returns it as a time.Time, parsed with layout as in time.Parse.
+If layout is "" time.RFC3339 is used. If not found the default value def is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) TimeFromSection(sectionName string, key string, layout string, def time.Time) time.Time {
+	val, err := config.LookupTime(sectionName, key, layout)
+
+	if err != nil {
+		return def
+	}
+	return val
+}
+
+/*
+Times looks for an array of times, parsed with layout, under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) Times(key string, layout string) []time.Time {
+	return config.TimesFromSection("", key, layout)
+}
+
+/*
+TimesFromSection looks for an array of times, parsed with layout, in the provided section and under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) TimesFromSection(sectionName string, key string, layout string) []time.Time {
+	val, _ := config.LookupTimes(sectionName, key, layout)
+	return val
+}
+
+/*
+LookupTime looks for the specified key and returns it as a time.Time, parsed with layout as in time.Parse.
+If layout is "" time.RFC3339 is used. If the key is missing, or can't be parsed, a *ValueError is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) LookupTime(sectionName string, key string, layout string) (time.Time, error) {
+	return lookupValue(config.sectionForName(sectionName), sectionName, key, parseTimeLayout(layout))
+}
+
+/*
+LookupTimes looks for an array of times, parsed with layout, in the provided section and under the provided key.
+If the key is missing, or any of the values can't be parsed, a *ValueError is returned.
+*/
+func (config *Config) LookupTimes(sectionName string, key string, layout string) ([]time.Time, error) {
+	return lookupValues(config.sectionForName(sectionName), sectionName, key, parseTimeLayout(layout))
+}
+
+/*
+Size looks for the specified key and returns it as a number of bytes. Sizes are written as a number followed by
+an optional unit, as in 512, 10MB or 1.5GiB. KB, MB, GB, TB and PB are powers of 1000, while KiB, MiB, GiB, TiB
+and PiB are powers of 1024. If not found the default value def is returned.
+*/
+func (config *Config) Size(key string, def int64) int64 {
+	return config.SizeFromSection("", key, def)
+}
+
+/*
+SizeFromSection looks for the specified key and returns it as a number of bytes, see Size for the format.
+If not found the default value def is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) SizeFromSection(sectionName string, key string, def int64) int64 {
+	val, err := config.LookupSize(sectionName, key)
+
+	if err != nil {
+		return def
+	}
+	return val
+}
+
+/*
+Sizes looks for an array of byte sizes under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) Sizes(key string) []int64 {
+	return config.SizesFromSection("", key)
+}
+
+/*
+SizesFromSection looks for an array of byte sizes in the provided section and under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) SizesFromSection(sectionName string, key string) []int64 {
+	val, _ := config.LookupSizes(sectionName, key)
+	return val
+}
+
+/*
+LookupSize looks for the specified key and returns it as a number of bytes, see Size for the format. If the key
+is missing, or can't be parsed, a *ValueError is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) LookupSize(sectionName string, key string) (int64, error) {
+	return lookupValue(config.sectionForName(sectionName), sectionName, key, parseSize)
+}
+
+/*
+LookupSizes looks for an array of byte sizes in the provided section and under the provided key. If the key
+is missing, or any of the values can't be parsed, a *ValueError is returned.
+*/
+func (config *Config) LookupSizes(sectionName string, key string) ([]int64, error) {
+	return lookupValues(config.sectionForName(sectionName), sectionName, key, parseSize)
+}
+
+/*
+Uint looks for the specified key and returns it as a uint64. If not found the default value def is returned.
+*/
+func (config *Config) Uint(key string, def uint64) uint64 {
+	return config.UintFromSection("", key, def)
+}
+
+/*
+UintFromSection looks for the specified key and returns it as a uint64. If not found the default value def is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) UintFromSection(sectionName string, key string, def uint64) uint64 {
+	val, err := config.LookupUint(sectionName, key)
+
+	if err != nil {
+		return def
+	}
+	return val
+}
+
+/*
+Uints looks for an array of uint64s under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) Uints(key string) []uint64 {
+	return config.UintsFromSection("", key)
+}
+
+/*
+UintsFromSection looks for an array of uint64s in the provided section and under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) UintsFromSection(sectionName string, key string) []uint64 {
+	val, _ := config.LookupUints(sectionName, key)
+	return val
+}
+
+/*
+LookupUint looks for the specified key and returns it as a uint64. If the key is missing, or can't be parsed,
+a *ValueError is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) LookupUint(sectionName string, key string) (uint64, error) {
+	return lookupValue(config.sectionForName(sectionName), sectionName, key, parseUint)
+}
+
+/*
+LookupUints looks for an array of uint64s in the provided section and under the provided key. If the key
+is missing, or any of the values can't be parsed, a *ValueError is returned.
+*/
+func (config *Config) LookupUints(sectionName string, key string) ([]uint64, error) {
+	return lookupValues(config.sectionForName(sectionName), sectionName, key, parseUint)
+}
+
+/*
+URL looks for the specified key and returns it as a *url.URL. If not found the default value def is returned.
+*/
+func (config *Config) URL(key string, def *url.URL) *url.URL {
+	return config.URLFromSection("", key, def)
+}
+
+/*
+URLFromSection looks for the specified key and returns it as a *url.URL. If not found the default value def is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) URLFromSection(sectionName string, key string, def *url.URL) *url.URL {
+	val, err := config.LookupURL(sectionName, key)
+
+	if err != nil {
+		return def
+	}
+	return val
+}
+
+/*
+URLs looks for an array of urls under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) URLs(key string) []*url.URL {
+	return config.URLsFromSection("", key)
+}
+
+/*
+URLsFromSection looks for an array of urls in the provided section and under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) URLsFromSection(sectionName string, key string) []*url.URL {
+	val, _ := config.LookupURLs(sectionName, key)
+	return val
+}
+
+/*
+LookupURL looks for the specified key and returns it as a *url.URL. If the key is missing, empty or can't be
+parsed, a *ValueError is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) LookupURL(sectionName string, key string) (*url.URL, error) {
+	return lookupValue(config.sectionForName(sectionName), sectionName, key, parseURL)
+}
+
+/*
+LookupURLs looks for an array of urls in the provided section and under the provided key. If the key
+is missing, or any of the values can't be parsed, a *ValueError is returned.
+*/
+func (config *Config) LookupURLs(sectionName string, key string) ([]*url.URL, error) {
+	return lookupValues(config.sectionForName(sectionName), sectionName, key, parseURL)
+}
+
+/*
+IP looks for the specified key and returns it as an IPv4 or IPv6 address. If not found the default value def is returned.
+*/
+func (config *Config) IP(key string, def netip.Addr) netip.Addr {
+	return config.IPFromSection("", key, def)
+}
+
+/*
+IPFromSection looks for the specified key and returns it as an IPv4 or IPv6 address. If not found the default
+value def is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) IPFromSection(sectionName string, key string, def netip.Addr) netip.Addr {
+	val, err := config.LookupIP(sectionName, key)
+
+	if err != nil {
+		return def
+	}
+	return val
+}
+
+/*
+IPs looks for an array of IP addresses under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) IPs(key string) []netip.Addr {
+	return config.IPsFromSection("", key)
+}
+
+/*
+IPsFromSection looks for an array of IP addresses in the provided section and under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) IPsFromSection(sectionName string, key string) []netip.Addr {
+	val, _ := config.LookupIPs(sectionName, key)
+	return val
+}
+
+/*
+LookupIP looks for the specified key and returns it as an IPv4 or IPv6 address. If the key is missing, or can't
+be parsed, a *ValueError is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) LookupIP(sectionName string, key string) (netip.Addr, error) {
+	return lookupValue(config.sectionForName(sectionName), sectionName, key, netip.ParseAddr)
+}
+
+/*
+LookupIPs looks for an array of IP addresses in the provided section and under the provided key. If the key
+is missing, or any of the values can't be parsed, a *ValueError is returned.
+*/
+func (config *Config) LookupIPs(sectionName string, key string) ([]netip.Addr, error) {
+	return lookupValues(config.sectionForName(sectionName), sectionName, key, netip.ParseAddr)
+}
+
+/*
+CIDR looks for the specified key and returns it as an IP network prefix, as in 10.0.0.0/8.
+If not found the default value def is returned.
+*/
+func (config *Config) CIDR(key string, def netip.Prefix) netip.Prefix {
+	return config.CIDRFromSection("", key, def)
+}
+
+/*
+CIDRFromSection looks for the specified key and returns it as an IP network prefix, as in 10.0.0.0/8.
+If not found the default value def is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) CIDRFromSection(sectionName string, key string, def netip.Prefix) netip.Prefix {
+	val, err := config.LookupCIDR(sectionName, key)
+
+	if err != nil {
+		return def
+	}
+	return val
+}
+
+/*
+CIDRs looks for an array of IP network prefixes under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) CIDRs(key string) []netip.Prefix {
+	return config.CIDRsFromSection("", key)
+}
+
+/*
+CIDRsFromSection looks for an array of IP network prefixes in the provided section and under the provided key.
+If no matches are found nil is returned.
+*/
+func (config *Config) CIDRsFromSection(sectionName string, key string) []netip.Prefix {
+	val, _ := config.LookupCIDRs(sectionName, key)
+	return val
+}
+
+/*
+LookupCIDR looks for the specified key and returns it as an IP network prefix. If the key is missing, or can't
+be parsed, a *ValueError is returned.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) LookupCIDR(sectionName string, key string) (netip.Prefix, error) {
+	return lookupValue(config.sectionForName(sectionName), sectionName, key, netip.ParsePrefix)
+}
+
+/*
+LookupCIDRs looks for an array of IP network prefixes in the provided section and under the provided key.
+If the key is missing, or any of the values can't be parsed, a *ValueError is returned.
+*/
+func (config *Config) LookupCIDRs(sectionName string, key string) ([]netip.Prefix, error) {
+	return lookupValues(config.sectionForName(sectionName), sectionName, key, netip.ParsePrefix)
+}
@@ -0,0 +1,186 @@
+package mini
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const typedIni = `timeout=30s
+started=2015-06-01T10:00:00Z
+day=2015-06-01
+cache=10MB
+heap=1.5GiB
+small=512
+count=18446744073709551615
+endpoint=https://example.com/api?x=1
+address=10.0.0.1
+address6=::1
+network=10.0.0.0/8
+bad=zipzap
+retries[]=1s
+retries[]=1m
+sizes[]=1KiB
+sizes[]=2kb
+hosts[]=192.168.0.1
+hosts[]=192.168.0.2
+nets[]=192.168.0.0/16
+links[]=http://a.example
+links[]=http://b.example
+dates[]=2015-06-01
+dates[]=2015-06-02
+counts[]=1
+counts[]=0x10
+
+[section]
+timeout=1h30m
+cache=1 kib`
+
+func TestDuration(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(typedIni))
+
+	assert.Nil(t, err, "Typed configuration should load without error.")
+
+	assert.Equal(t, config.Duration("timeout", 0), 30*time.Second, "Read value of timeout wrong")
+	assert.Equal(t, config.DurationFromSection("section", "timeout", 0), 90*time.Minute, "Read value of timeout in section wrong")
+	assert.Equal(t, config.Duration("bad", time.Second), time.Second, "Default value of duration wrong on parse error")
+	assert.Equal(t, config.Duration("missing", time.Second), time.Second, "Default value of duration wrong")
+	assert.Equal(t, config.Durations("retries"), []time.Duration{time.Second, time.Minute}, "Read value of retries wrong")
+	assert.Nil(t, config.Durations("counts"), "Default value of durations wrong on parse error")
+}
+
+func TestTime(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(typedIni))
+
+	assert.Nil(t, err, "Typed configuration should load without error.")
+
+	started := time.Date(2015, 6, 1, 10, 0, 0, 0, time.UTC)
+	day := time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, config.Time("started", "", time.Time{}).Equal(started), "Read value of started wrong")
+	assert.True(t, config.Time("day", "2006-01-02", time.Time{}).Equal(day), "Read value of day wrong")
+	assert.True(t, config.Time("day", "", started).Equal(started), "Default value of time wrong on parse error")
+	assert.Equal(t, len(config.Times("dates", "2006-01-02")), 2, "Read value of dates wrong")
+	assert.Nil(t, config.Times("dates", ""), "Default value of times wrong on parse error")
+}
+
+func TestSize(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(typedIni))
+
+	assert.Nil(t, err, "Typed configuration should load without error.")
+
+	assert.Equal(t, config.Size("cache", 0), int64(10000000), "Read value of cache wrong")
+	assert.Equal(t, config.Size("heap", 0), int64(1610612736), "Read value of heap wrong")
+	assert.Equal(t, config.Size("small", 0), int64(512), "Read value of small wrong")
+	assert.Equal(t, config.SizeFromSection("section", "cache", 0), int64(1024), "Read value of cache in section wrong")
+	assert.Equal(t, config.Size("bad", 7), int64(7), "Default value of size wrong on parse error")
+	assert.Equal(t, config.Sizes("sizes"), []int64{1024, 2000}, "Read value of sizes wrong")
+
+	for _, bad := range []string{"", "MB", "10XB", "-1", "1.2.3KB", "1e30PB"} {
+		_, err := parseSize(bad)
+		assert.NotNil(t, err, "Size "+bad+" should not parse")
+	}
+}
+
+func TestUint(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(typedIni))
+
+	assert.Nil(t, err, "Typed configuration should load without error.")
+
+	assert.Equal(t, config.Uint("count", 0), uint64(18446744073709551615), "Read value of count wrong")
+	assert.Equal(t, config.Uint("bad", 3), uint64(3), "Default value of uint wrong on parse error")
+	assert.Equal(t, config.Uints("counts"), []uint64{1, 16}, "Read value of counts wrong")
+}
+
+func TestURL(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(typedIni))
+
+	assert.Nil(t, err, "Typed configuration should load without error.")
+
+	endpoint := config.URL("endpoint", nil)
+	assert.NotNil(t, endpoint, "endpoint should not be nil")
+	assert.Equal(t, endpoint.Host, "example.com", "Read value of endpoint wrong")
+	assert.Equal(t, endpoint.Query().Get("x"), "1", "Read value of endpoint wrong")
+
+	def, _ := url.Parse("http://localhost")
+	assert.Equal(t, config.URL("missing", def), def, "Default value of url wrong")
+	assert.Equal(t, len(config.URLs("links")), 2, "Read value of links wrong")
+}
+
+func TestIPAndCIDR(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(typedIni))
+
+	assert.Nil(t, err, "Typed configuration should load without error.")
+
+	assert.Equal(t, config.IP("address", netip.Addr{}), netip.MustParseAddr("10.0.0.1"), "Read value of address wrong")
+	assert.Equal(t, config.IP("address6", netip.Addr{}), netip.IPv6Loopback(), "Read value of address6 wrong")
+	assert.False(t, config.IP("bad", netip.Addr{}).IsValid(), "Default value of ip wrong on parse error")
+	assert.Equal(t, config.IPs("hosts"), []netip.Addr{netip.MustParseAddr("192.168.0.1"), netip.MustParseAddr("192.168.0.2")}, "Read value of hosts wrong")
+
+	assert.Equal(t, config.CIDR("network", netip.Prefix{}), netip.MustParsePrefix("10.0.0.0/8"), "Read value of network wrong")
+	assert.True(t, config.CIDR("network", netip.Prefix{}).Contains(netip.MustParseAddr("10.1.2.3")), "Network should contain address")
+	assert.Equal(t, config.CIDRs("nets"), []netip.Prefix{netip.MustParsePrefix("192.168.0.0/16")}, "Read value of nets wrong")
+	assert.Nil(t, config.CIDRs("hosts"), "Default value of cidrs wrong on parse error")
+}
+
+func TestLookupErrors(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(typedIni))
+
+	assert.Nil(t, err, "Typed configuration should load without error.")
+
+	_, err = config.LookupDuration("", "missing")
+	assert.True(t, errors.Is(err, ErrNotFound), "Missing key should be not found")
+
+	_, err = config.LookupDuration("missing", "timeout")
+	assert.True(t, errors.Is(err, ErrNotFound), "Missing section should be not found")
+
+	_, err = config.LookupDuration("", "retries")
+	assert.True(t, errors.Is(err, ErrNotFound), "Array should be not found as a single value")
+
+	_, err = config.LookupSize("section", "timeout")
+	var valueErr *ValueError
+	assert.True(t, errors.As(err, &valueErr), "Parse error should be a ValueError")
+	assert.Equal(t, valueErr.Section, "section", "Error should name the section")
+	assert.Equal(t, valueErr.Key, "timeout", "Error should name the key")
+	assert.Equal(t, valueErr.Value, "1h30m", "Error should include the value")
+	assert.False(t, errors.Is(err, ErrNotFound), "Parse error isn't not found")
+	assert.Equal(t, err.Error(), `mini: key "timeout" in section "section": invalid size "1h30m"`, "Error message wrong")
+
+	_, err = config.LookupURLs("", "hosts")
+	assert.Nil(t, err, "Hosts should parse as urls")
+
+	val, err := config.LookupUint("", "count")
+	assert.Nil(t, err, "count should parse")
+	assert.Equal(t, val, uint64(18446744073709551615), "Read value of count wrong")
+}
+
+type durationStruct struct {
+	Timeout time.Duration
+	Retries []time.Duration
+	Missing time.Duration
+}
+
+func TestLoadStructDurations(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(typedIni))
+
+	assert.Nil(t, err, "Typed configuration should load without error.")
+
+	data := durationStruct{Missing: time.Hour}
+
+	assert.True(t, config.DataFromSection("", &data), "load should succeed")
+	assert.Equal(t, data.Timeout, 30*time.Second, "Read value of timeout wrong")
+	assert.Equal(t, data.Retries, []time.Duration{time.Second, time.Minute}, "Read value of retries wrong")
+	assert.Equal(t, data.Missing, time.Hour, "Missing duration should keep its value")
+}
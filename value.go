@@ -0,0 +1,632 @@
+package mini
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+/*
+Kind describes how a value was written in the file.
+*/
+type Kind int
+
+const (
+	//ScalarValue is a single value written as key=value, including inline arrays like key=[a, b]
+	ScalarValue Kind = iota
+	//ArrayValue is an array written with repeated keys in the form key[]=value
+	ArrayValue
+	//MapValue is a map written with keys in the form key[name]=value
+	MapValue
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case ScalarValue:
+		return "scalar"
+	case ArrayValue:
+		return "array"
+	case MapValue:
+		return "map"
+	}
+	return "Kind(" + strconv.Itoa(int(kind)) + ")"
+}
+
+/*
+Position is the location of a value in a configuration file.
+*/
+type Position struct {
+	File string //the path the config was loaded from, "" if it was read from a reader
+	Line int    //the line number, starting at 1
+}
+
+func (pos Position) String() string {
+	if len(pos.File) == 0 {
+		return "line " + strconv.Itoa(pos.Line)
+	}
+	return pos.File + ":" + strconv.Itoa(pos.Line)
+}
+
+//The stored form of a value. Conversions to other types are done the first time they are asked for and kept.
+type value struct {
+	raw  string //text from the file with the surrounding quotes removed and escapes intact
+	src  *source
+	more *composite //set for arrays, maps, inline arrays and references to secrets
+	conv atomic.Pointer[conversions]
+
+	line       int32
+	occurrence int32 //which appearance of a split section the value was written in, starting at 1
+}
+
+//What the values loaded from one file share
+type source struct {
+	file    string
+	options *Options
+}
+
+//The parts of a value that most scalars don't need
+type composite struct {
+	kind    Kind
+	secret  bool           //the value is shown as Redacted
+	entries []*value       //array entries, the entries of an inline array, or map entries
+	names   []string       //map names in the order they first appeared, names[i] is the name of entries[i]
+	index   map[string]int //positions of map entries by name, once a map is too long to search
+
+	ref *secretRef //set if the value is a reference to a secret
+}
+
+//Maps with more entries than this are searched with an index
+const mapIndexAfter = 8
+
+//The value converted to each of the basic types
+type conversions struct {
+	text    string
+	integer int64
+	float   float64
+	boolean bool
+
+	textOK    bool
+	integerOK bool
+	floatOK   bool
+	booleanOK bool
+}
+
+//Used by values that weren't loaded from a file, like the zero Value
+var defaultSource = &source{options: &Options{}}
+
+//Create a scalar value, line is its line in the file of src
+func newValue(raw string, line int, src *source) *value {
+	return new(value).init(raw, line, src)
+}
+
+//Values are allocated a block at a time while a file is loaded, rather than one at a time. Blocks start small
+//and double as more values are read, so a small file doesn't pay for a large block
+type valueBlock struct {
+	free []value
+	size int
+}
+
+//Return the next unused value in the block, starting a new block when it runs out
+func (block *valueBlock) next() *value {
+	if len(block.free) == 0 {
+		block.size = min(max(2*block.size, 16), 64)
+		block.free = make([]value, block.size)
+	}
+
+	v := &block.free[0]
+	block.free = block.free[1:]
+	return v
+}
+
+//Set up v as a value, line is its line in the file of src. With Options.InlineLists a value written as [a, b, c] is a list
+func (v *value) init(raw string, line int, src *source) *value {
+	v.initScalar(raw, line, src)
+
+	if src.options.InlineLists {
+		v.parseList()
+	}
+
+	return v
+}
+
+//Set up v as a value that is never an inline list, like a value written in quotes
+func (v *value) initScalar(raw string, line int, src *source) *value {
+	v.raw, v.src, v.line = raw, src, int32(line)
+	options := src.options
+
+	if len(options.SecretResolvers) > 0 {
+		if ref := options.secretRef(raw); ref != nil {
+			more := v.extra()
+			more.ref = ref
+			more.secret = true
+		}
+	}
+
+	return v
+}
+
+//Read a value written as [a, b, c] as an inline list, the entries are scalars
+func (v *value) parseList() {
+	list, ok := parseInlineList(v.raw)
+
+	if !ok {
+		return
+	}
+
+	entries := make([]*value, len(list))
+
+	for i, entry := range list {
+		entries[i] = new(value).initScalar(entry, int(v.line), v.src)
+	}
+
+	v.extra().entries = entries
+}
+
+//Return the composite part of the value, adding it if it's missing
+func (v *value) extra() *composite {
+	if v.more == nil {
+		v.more = new(composite)
+	}
+	return v.more
+}
+
+//Return how the value was written
+func (v *value) kind() Kind {
+	if v.more == nil {
+		return ScalarValue
+	}
+	return v.more.kind
+}
+
+//Check if the value is shown as Redacted
+func (v *value) isSecret() bool {
+	return v.more != nil && v.more.secret
+}
+
+//Return the entries of an array or inline array, nil for other values
+func (v *value) entries() []*value {
+	if v.more == nil || v.more.kind == MapValue {
+		return nil
+	}
+	return v.more.entries
+}
+
+//Return the entries of a map in the order their names first appeared, nil for other values
+func (v *value) mapEntries() []*value {
+	if v.more == nil || v.more.kind != MapValue {
+		return nil
+	}
+	return v.more.entries
+}
+
+//Return the names of a map in the order they first appeared, nil for other values
+func (v *value) names() []string {
+	if v.more == nil || v.more.kind != MapValue {
+		return nil
+	}
+	return v.more.names
+}
+
+//Return the error from resolving a reference to a secret
+func (v *value) failed() error {
+	if v.more == nil || v.more.ref == nil {
+		return nil
+	}
+	return v.more.ref.err
+}
+
+//Return where the value was found
+func (v *value) position() Position {
+	return Position{File: v.src.file, Line: int(v.line)}
+}
+
+/*
+Return the value converted to each of the basic types, converting it the first time. Values can be read
+from several goroutines, if they race each one converts the value and one of the results is kept.
+*/
+func (v *value) converted() *conversions {
+	if c := v.conv.Load(); c != nil {
+		return c
+	}
+
+	c := new(conversions)
+	raw := v.raw
+
+	if strings.ContainsAny(raw, "\\\"\n") {
+		c.text, c.textOK = unquote(raw)
+	} else {
+		c.text, c.textOK = raw, true
+	}
+
+	//strconv allocates an error for values it can't parse, so skip values that can't be numbers
+	if looksLikeInteger(raw) {
+		var err error
+		c.integer, err = strconv.ParseInt(raw, 0, 64)
+		c.integerOK = err == nil
+	}
+
+	if looksLikeFloat(raw) {
+		var err error
+		c.float, err = strconv.ParseFloat(raw, 64)
+		c.floatOK = err == nil
+	}
+
+	c.boolean, c.booleanOK = v.src.options.booleanValue(raw)
+
+	v.conv.Store(c)
+	return c
+}
+
+//Decode the escapes in a string, as in a go string literal
+func unquote(raw string) (string, bool) {
+	str, err := strconv.Unquote("\"" + raw + "\"")
+	return str, err == nil
+}
+
+//Check for the characters allowed by strconv.ParseInt with base 0
+func looksLikeInteger(raw string) bool {
+	if len(raw) > 0 && (raw[0] == '-' || raw[0] == '+') {
+		raw = raw[1:]
+	}
+
+	if len(raw) == 0 || raw[0] < '0' || raw[0] > '9' {
+		return false
+	}
+
+	for i := 1; i < len(raw); i++ {
+		c := raw[i]
+
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_') {
+			return false
+		}
+	}
+
+	return true
+}
+
+//Check for the characters allowed by strconv.ParseFloat
+func looksLikeFloat(raw string) bool {
+	if len(raw) > 0 && (raw[0] == '-' || raw[0] == '+') {
+		raw = raw[1:]
+	}
+
+	if len(raw) == 0 {
+		return false
+	}
+
+	if c := raw[0]; c == 'i' || c == 'I' || c == 'n' || c == 'N' {
+		return strings.EqualFold(raw, "inf") || strings.EqualFold(raw, "infinity") || strings.EqualFold(raw, "nan")
+	}
+
+	if (raw[0] < '0' || raw[0] > '9') && raw[0] != '.' {
+		return false
+	}
+
+	for i := 1; i < len(raw); i++ {
+		c := raw[i]
+
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F' ||
+			c == 'x' || c == 'X' || c == 'p' || c == 'P' || c == '_' || c == '.' || c == '+' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
+
+//Add an entry to an array value, creating the array if v isn't one, and return the array
+func (v *value) appendEntry(entry *value) *value {
+	if v == nil || v.kind() != ArrayValue {
+		v = &value{raw: entry.raw, src: entry.src, line: entry.line, occurrence: entry.occurrence}
+		v.more = &composite{kind: ArrayValue, entries: make([]*value, 0, 4)}
+	}
+
+	v.more.entries = append(v.more.entries, entry)
+	return v
+}
+
+//Set an entry in a map value, creating the map if v isn't one, and return the map
+func (v *value) setEntry(name string, entry *value) *value {
+	if v == nil || v.kind() != MapValue {
+		v = &value{src: entry.src, line: entry.line, occurrence: entry.occurrence}
+		v.more = &composite{kind: MapValue, entries: make([]*value, 0, 4), names: make([]string, 0, 4)}
+	}
+
+	more := v.more
+
+	if i, ok := more.find(name); ok {
+		more.entries[i] = entry
+		return v
+	}
+
+	more.entries = append(more.entries, entry)
+	more.names = append(more.names, name)
+
+	if more.index != nil || len(more.names) > mapIndexAfter {
+		if more.index == nil {
+			more.index = make(map[string]int, len(more.names))
+			for i, n := range more.names {
+				more.index[n] = i
+			}
+		}
+		more.index[name] = len(more.names) - 1
+	}
+
+	return v
+}
+
+//Return the position of the map entry with the given name
+func (more *composite) find(name string) (int, bool) {
+	if more.index != nil {
+		i, ok := more.index[name]
+		return i, ok
+	}
+
+	for i, n := range more.names {
+		if n == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+//Return the entry with the given name in a map value
+func (v *value) mapEntry(name string) (*value, bool) {
+	if v == nil || v.kind() != MapValue {
+		return nil, false
+	}
+
+	if i, ok := v.more.find(name); ok {
+		return v.more.entries[i], true
+	}
+	return nil, false
+}
+
+//Return the entries of an array or inline array, or the value itself as an array of 1
+func (v *value) list() []*value {
+	if v.kind() == MapValue {
+		return nil
+	}
+
+	if entries := v.entries(); entries != nil {
+		return entries
+	}
+	return []*value{v}
+}
+
+/*
+Value is a value from a config, along with where it was found. Values are returned by Lookup. The text of
+a value is converted the first time it is read as a particular type and the result is kept, so the
+conversions are cheap to call again.
+
+The zero Value is an empty scalar.
+*/
+type Value struct {
+	v *value
+}
+
+//The value behind the zero Value
+var emptyValue = &value{src: defaultSource}
+
+func (val Value) value() *value {
+	if val.v == nil {
+		return emptyValue
+	}
+	return val.v.resolve()
+}
+
+/*
+Kind returns how the value was written in the file.
+*/
+func (val Value) Kind() Kind {
+	return val.value().kind()
+}
+
+/*
+Position returns where the value was found. For arrays this is the first entry and for maps the first name.
+*/
+func (val Value) Position() Position {
+	return val.value().position()
+}
+
+/*
+Raw returns the value as it was written in the file, without surrounding quotes and with escapes intact.
+Maps return "" and arrays return the first entry. References to secrets are returned unresolved.
+*/
+func (val Value) Raw() string {
+	if val.v == nil {
+		return ""
+	}
+	return val.v.raw
+}
+
+/*
+String returns the value with its escapes decoded, or the raw text if the escapes are invalid.
+Arrays are returned in the form [a, b] and maps in the form map[name:value].
+Secret values are returned as Redacted.
+*/
+func (val Value) String() string {
+	if val.Secret() {
+		return Redacted
+	}
+
+	v := val.value()
+
+	switch v.kind() {
+	case ArrayValue:
+		entries := make([]string, len(v.entries()))
+		for i, entry := range v.entries() {
+			entries[i] = Value{entry}.String()
+		}
+		return "[" + strings.Join(entries, ", ") + "]"
+	case MapValue:
+		entries := make([]string, len(v.mapEntries()))
+		for i, entry := range v.mapEntries() {
+			entries[i] = v.more.names[i] + ":" + Value{entry}.String()
+		}
+		return "map[" + strings.Join(entries, " ") + "]"
+	}
+
+	if c := v.converted(); c.textOK {
+		return c.text
+	}
+	return v.raw
+}
+
+/*
+Text returns the value with its escapes decoded, or an error if the escapes are invalid or the value isn't a scalar.
+*/
+func (val Value) Text() (string, error) {
+	v := val.value()
+
+	if v.kind() != ScalarValue {
+		return "", v.kindError()
+	}
+
+	if err := v.failed(); err != nil {
+		return "", err
+	}
+
+	c := v.converted()
+
+	if !c.textOK {
+		_, err := strconv.Unquote("\"" + v.raw + "\"")
+		return "", fmt.Errorf("mini: invalid string %q: %w", v.displayText(), err)
+	}
+	return c.text, nil
+}
+
+/*
+Int returns the value as an int64, or an error if it isn't a scalar integer.
+*/
+func (val Value) Int() (int64, error) {
+	v := val.value()
+
+	if v.kind() != ScalarValue {
+		return 0, v.kindError()
+	}
+
+	if err := v.failed(); err != nil {
+		return 0, err
+	}
+
+	c := v.converted()
+
+	if !c.integerOK {
+		_, err := strconv.ParseInt(v.raw, 0, 64)
+		return 0, v.redactError(err)
+	}
+	return c.integer, nil
+}
+
+/*
+Float returns the value as a float64, or an error if it isn't a scalar number.
+*/
+func (val Value) Float() (float64, error) {
+	v := val.value()
+
+	if v.kind() != ScalarValue {
+		return 0, v.kindError()
+	}
+
+	if err := v.failed(); err != nil {
+		return 0, err
+	}
+
+	c := v.converted()
+
+	if !c.floatOK {
+		_, err := strconv.ParseFloat(v.raw, 64)
+		return 0, v.redactError(err)
+	}
+	return c.float, nil
+}
+
+/*
+Bool returns the value as a bool, using the vocabulary in the config's Options, or an error if it isn't a scalar boolean.
+*/
+func (val Value) Bool() (bool, error) {
+	v := val.value()
+
+	if v.kind() != ScalarValue {
+		return false, v.kindError()
+	}
+
+	if err := v.failed(); err != nil {
+		return false, err
+	}
+
+	c := v.converted()
+
+	if !c.booleanOK {
+		return false, fmt.Errorf("mini: invalid boolean %q", v.displayText())
+	}
+	return c.boolean, nil
+}
+
+/*
+Len returns the number of entries in an array, inline array or map. Other scalars have a length of 1.
+*/
+func (val Value) Len() int {
+	v := val.value()
+
+	if v.kind() == MapValue {
+		return len(v.mapEntries())
+	}
+	return len(v.list())
+}
+
+/*
+Index returns the i'th entry in an array or inline array. A scalar is its own first entry.
+Index panics if i is out of range, or the value is a map.
+*/
+func (val Value) Index(i int) Value {
+	v := val.value()
+
+	if v.kind() == MapValue {
+		panic("mini: Index of a map value")
+	}
+	return Value{v.list()[i]}
+}
+
+/*
+Names returns the names in a map, in the order they first appeared in the file, or nil for other values.
+*/
+func (val Value) Names() []string {
+	v := val.value()
+
+	if v.kind() != MapValue {
+		return nil
+	}
+	return append([]string(nil), v.names()...)
+}
+
+/*
+Get returns the entry in a map with the given name. Unlike keys, names are case sensitive.
+*/
+func (val Value) Get(name string) (Value, bool) {
+	if entry, ok := val.value().mapEntry(name); ok {
+		return Value{entry}, true
+	}
+	return Value{}, false
+}
+
+func (v *value) kindError() error {
+	return fmt.Errorf("mini: %s value at %s is not a scalar", v.kind(), v.position())
+}
+
+/*
+Lookup returns the value stored under key in the section named sectionName. Lookup follows the same rules as the
+other getters, including Options.InheritValues and Options.Extends. The boolean is false if the key is missing.
+
+If the section name matches the config.name or "" the global data is searched.
+*/
+func (config *Config) Lookup(sectionName string, key string) (Value, bool) {
+	v, ok := config.sectionForName(sectionName).lookup(key)
+
+	if !ok {
+		return Value{}, false
+	}
+	return Value{v}, true
+}
@@ -0,0 +1,176 @@
+package mini
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const valueIni = `name="hello\tworld"
+count=0x20
+ratio=2.5
+debug=true
+bad=\q
+hosts=[a, b]
+
+[section]
+list[]=1
+list[]=2
+headers[Accept]=text/html
+headers[Host]=example.com`
+
+func TestLookup(t *testing.T) {
+
+	config := &Config{Options: Options{InlineLists: true}}
+	err := config.InitializeFromReader(strings.NewReader(valueIni))
+
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	val, ok := config.Lookup("", "NAME")
+	assert.True(t, ok, "name should be found")
+	assert.Equal(t, val.Kind(), ScalarValue, "name should be a scalar")
+	assert.Equal(t, val.Raw(), `hello\tworld`, "Raw should keep escapes")
+	assert.Equal(t, val.String(), "hello\tworld", "String should decode escapes")
+	assert.Equal(t, val.Position(), Position{Line: 1}, "Read position of name wrong")
+
+	text, err := val.Text()
+	assert.Nil(t, err, "Text should decode")
+	assert.Equal(t, text, "hello\tworld", "Read text of name wrong")
+
+	_, err = val.Int()
+	assert.NotNil(t, err, "name isn't an integer")
+
+	val, _ = config.Lookup("", "count")
+	i, err := val.Int()
+	assert.Nil(t, err, "count should be an integer")
+	assert.Equal(t, i, int64(32), "Read value of count wrong")
+
+	val, _ = config.Lookup("", "ratio")
+	f, err := val.Float()
+	assert.Nil(t, err, "ratio should be a float")
+	assert.Equal(t, f, 2.5, "Read value of ratio wrong")
+
+	val, _ = config.Lookup("", "debug")
+	b, err := val.Bool()
+	assert.Nil(t, err, "debug should be a boolean")
+	assert.Equal(t, b, true, "Read value of debug wrong")
+
+	val, _ = config.Lookup("", "bad")
+	_, err = val.Text()
+	assert.NotNil(t, err, "Invalid escapes should be an error")
+	assert.Equal(t, val.String(), `\q`, "String should fall back to the raw text")
+
+	val, _ = config.Lookup("", "hosts")
+	assert.Equal(t, val.Kind(), ScalarValue, "Inline arrays are scalars")
+	assert.Equal(t, val.Len(), 2, "Inline arrays have entries")
+	assert.Equal(t, val.Index(1).String(), "b", "Read entry of hosts wrong")
+
+	_, ok = config.Lookup("", "missing")
+	assert.False(t, ok, "Missing key should not be found")
+
+	_, ok = config.Lookup("missing", "name")
+	assert.False(t, ok, "Missing section should not be found")
+}
+
+func TestLookupArraysAndMaps(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(valueIni))
+
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	val, ok := config.Lookup("section", "list")
+	assert.True(t, ok, "list should be found")
+	assert.Equal(t, val.Kind(), ArrayValue, "list should be an array")
+	assert.Equal(t, val.Len(), 2, "list should have 2 entries")
+	assert.Equal(t, val.Position().Line, 9, "Array position should be the first entry")
+	assert.Equal(t, val.Index(1).Position().Line, 10, "Entry position should be its line")
+	assert.Equal(t, val.String(), "[1, 2]", "Read string of list wrong")
+
+	_, err = val.Int()
+	assert.NotNil(t, err, "Arrays aren't integers")
+
+	val, ok = config.Lookup("section", "headers")
+	assert.True(t, ok, "headers should be found")
+	assert.Equal(t, val.Kind(), MapValue, "headers should be a map")
+	assert.Equal(t, val.Names(), []string{"Accept", "Host"}, "Read names of headers wrong")
+	assert.Equal(t, val.String(), "map[Accept:text/html Host:example.com]", "Read string of headers wrong")
+
+	host, ok := val.Get("Host")
+	assert.True(t, ok, "Host should be found")
+	assert.Equal(t, host.String(), "example.com", "Read value of Host wrong")
+	assert.Equal(t, host.Position().Line, 12, "Read position of Host wrong")
+
+	_, ok = val.Get("host")
+	assert.False(t, ok, "Map names are case sensitive")
+
+	var zero Value
+	assert.Equal(t, zero.Kind(), ScalarValue, "Zero value should be a scalar")
+	assert.Equal(t, zero.String(), "", "Zero value should be empty")
+}
+
+func TestLookupPositionFromFile(t *testing.T) {
+
+	filepath := path.Join(os.TempDir(), "valueini.txt")
+	f, err := os.Create(filepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(filepath)
+	if _, err := f.WriteString(valueIni); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfiguration(filepath)
+
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	val, ok := config.Lookup("section", "list")
+	assert.True(t, ok, "list should be found")
+	assert.Equal(t, val.Position(), Position{File: filepath, Line: 9}, "Read position of list wrong")
+	assert.Equal(t, val.Position().String(), filepath+":9", "Read position string wrong")
+}
+
+func TestLookupInherited(t *testing.T) {
+
+	config := &Config{Options: Options{InheritValues: true}}
+	err := config.InitializeFromReader(strings.NewReader("port=80\n[server]\nhost=a"))
+
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	val, ok := config.Lookup("server", "port")
+	assert.True(t, ok, "port should be inherited")
+	assert.Equal(t, val.Position().Line, 1, "Inherited value should keep its position")
+}
+
+func TestConvertedOnFirstRead(t *testing.T) {
+
+	config, err := LoadConfigurationFromReader(strings.NewReader(valueIni))
+
+	assert.Nil(t, err, "Configuration should load without error.")
+
+	val, _ := config.Lookup("", "count")
+	assert.Nil(t, val.v.conv.Load(), "Values should not be converted when they are loaded")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, config.Integer("count", 0), int64(32), "Read value of count wrong")
+		}()
+	}
+	wg.Wait()
+
+	converted := val.v.conv.Load()
+	assert.NotNil(t, converted, "Conversions should be kept after the first read")
+
+	n, _ := val.Int()
+	assert.Equal(t, n, int64(32), "Read value of count wrong")
+	assert.True(t, val.v.conv.Load() == converted, "Later reads should use the kept conversions")
+}